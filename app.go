@@ -85,14 +85,27 @@ func (a *App) GetStartupFiles() []string {
 // benötigt wird (z.B. Dialoge öffnen, Events senden).
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	startRecovery()
 }
 
 // shutdown wird von Wails beim Beenden der App aufgerufen.
 func (a *App) shutdown(ctx context.Context) {
+	cancelAllSearches()
+	cancelAllFileTransfers()
+	cancelAllTasks()
+	stopAllSearchIndexes()
+	stopFileWatcher()
+	closeAllLargeFiles()
+	stopRecovery()
 }
 
 // domReady wird aufgerufen, sobald das Frontend (HTML/JS) vollständig geladen ist.
 func (a *App) domReady(ctx context.Context) {
+	// Ungespeicherte Tabs einer abgestürzten Sitzung anbieten
+	if sessions := a.GetRecoverableSessions(); len(sessions) > 0 {
+		runtime.EventsEmit(ctx, "recovery_available", sessions)
+	}
+
 	runtime.OnFileDrop(ctx, func(x, y int, paths []string) {
 		fmt.Printf("Files dropped: %v\n", paths)
 		seen := make(map[string]bool)
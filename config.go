@@ -27,6 +27,11 @@ type AppConfig struct {
 	OpenRouterApiKey string          `json:"openrouter_api_key"` // AES-GCM verschlüsselt
 	GeminiApiKey     string          `json:"gemini_api_key"`     // AES-GCM verschlüsselt
 	RecentProjects   []RecentProject `json:"recent_projects"`
+	ShowHiddenFiles  bool            `json:"show_hidden_files"` // Dateien mit Punkt am Anfang im Explorer
+
+	// Weitere Editor-Einstellungen und solche pro Sprache (siehe editorSettings.go)
+	Editor    EditorOverrides            `json:"editor"`
+	Languages map[string]EditorOverrides `json:"languages,omitempty"`
 }
 
 // getConfigPath gibt den Pfad zur Konfigurationsdatei zurück
@@ -42,6 +47,20 @@ func (a *App) getConfigPath() string {
 	return filepath.Join(appDir, "config.json")
 }
 
+// getAppDataDir gibt einen Unterordner des Leoedit-Konfigurationsverzeichnisses
+// zurück (z.B. ~/.config/Leoedit/index) und legt ihn bei Bedarf an.
+func getAppDataDir(name string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(configDir, "Leoedit", name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
 // loadConfig lädt die Konfiguration von der Festplatte
 func (a *App) loadConfig() {
 	data, err := os.ReadFile(a.configPath)
@@ -72,26 +91,25 @@ func (a *App) saveConfig() error {
 	return os.WriteFile(a.configPath, data, 0644)
 }
 
-// EditorSettings wird vom Frontend verwendet
+// EditorSettings wird vom Frontend verwendet. Font und FontSize sind die
+// globalen Einstellungen; die übrigen Felder und die Werte für eine
+// bestimmte Datei liefert GetEffectiveSettings (siehe editorSettings.go).
 type EditorSettings struct {
-	Font     string `json:"font"`
-	FontSize int    `json:"fontSize"`
+	Font                   string `json:"font"`
+	FontSize               int    `json:"fontSize"`
+	TabSize                int    `json:"tabSize"`
+	InsertSpaces           bool   `json:"insertSpaces"`
+	TrimTrailingWhitespace bool   `json:"trimTrailingWhitespace"`
+	InsertFinalNewline     bool   `json:"insertFinalNewline"`
+	LineEnding             string `json:"lineEnding"`  // "lf", "crlf", "cr" oder "" (wie Datei)
+	Language               string `json:"language"`    // Dateityp, z.B. "go"
+	ProjectRoot            string `json:"projectRoot"` // Projekt, dessen Einstellungen gelten
 }
 
 // GetEditorSettings gibt die aktuellen Editor-Einstellungen zurück
+// (Standardwerte und globale Einstellungen, ohne Projekt)
 func (a *App) GetEditorSettings() EditorSettings {
-	font := a.Config.EditorFont
-	if font == "" {
-		font = "JetBrains Mono, monospace"
-	}
-	fontSize := a.Config.EditorFontSize
-	if fontSize == 0 {
-		fontSize = 14
-	}
-	return EditorSettings{
-		Font:     font,
-		FontSize: fontSize,
-	}
+	return a.GetEffectiveSettings("")
 }
 
 // SetEditorSettings speichert die Editor-Einstellungen
@@ -100,3 +118,14 @@ func (a *App) SetEditorSettings(font string, fontSize int) error {
 	a.Config.EditorFontSize = fontSize
 	return a.saveConfig()
 }
+
+// GetShowHiddenFiles gibt zurück, ob der Explorer versteckte Dateien anzeigt
+func (a *App) GetShowHiddenFiles() bool {
+	return a.Config.ShowHiddenFiles
+}
+
+// SetShowHiddenFiles schaltet die Anzeige versteckter Dateien um und speichert sie
+func (a *App) SetShowHiddenFiles(show bool) error {
+	a.Config.ShowHiddenFiles = show
+	return a.saveConfig()
+}
@@ -0,0 +1,261 @@
+// diff.go — Zeilenbasierter Vergleich zweier Texte (Myers-Algorithmus).
+// Liefert Hunks mit Kontextzeilen wie "diff -u", aber als Structs für das
+// Frontend. Wird von der lokalen Dateihistorie (history.go) verwendet.
+package main
+
+import "strings"
+
+const (
+	diffContextLines = 3
+	// Ab so vielen Änderungen wird nicht mehr die kürzeste Folge gesucht,
+	// sondern der geänderte Bereich komplett ersetzt (Speicher O(D²))
+	diffMaxEdits = 2000
+)
+
+// DiffLine ist eine Zeile eines Hunks.
+// Kind: " " (unverändert), "-" (entfernt) oder "+" (hinzugefügt).
+// OldLine/NewLine sind 1-basiert und 0, wenn die Zeile dort nicht existiert.
+type DiffLine struct {
+	Kind    string `json:"kind"`
+	Text    string `json:"text"`
+	OldLine int    `json:"oldLine"`
+	NewLine int    `json:"newLine"`
+}
+
+// DiffHunk ist ein zusammenhängender geänderter Bereich mit Kontext.
+type DiffHunk struct {
+	OldStart int        `json:"oldStart"`
+	OldLines int        `json:"oldLines"`
+	NewStart int        `json:"newStart"`
+	NewLines int        `json:"newLines"`
+	Lines    []DiffLine `json:"lines"`
+}
+
+// diffEdit ist eine einzelne Operation der Edit-Folge.
+// Bei ' ' sind beide Indizes gesetzt, bei '-' nur oldIdx, bei '+' nur newIdx.
+type diffEdit struct {
+	kind   byte
+	oldIdx int
+	newIdx int
+}
+
+// splitDiffLines zerlegt Text in Zeilen ohne Zeilenende.
+func splitDiffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// diffTexts vergleicht zwei Texte und liefert die Hunks sowie die Anzahl
+// hinzugefügter und entfernter Zeilen.
+func diffTexts(oldText, newText string) ([]DiffHunk, int, int) {
+	a, b := splitDiffLines(oldText), splitDiffLines(newText)
+	edits := diffLines(a, b)
+
+	added, removed := 0, 0
+	for _, e := range edits {
+		switch e.kind {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	return buildHunks(edits, a, b), added, removed
+}
+
+// diffLines berechnet die Edit-Folge von a nach b. Gemeinsamer Anfang und
+// gemeinsames Ende werden vorab abgeschnitten.
+func diffLines(a, b []string) []diffEdit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]diffEdit, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, diffEdit{kind: ' ', oldIdx: i, newIdx: i})
+	}
+	for _, e := range myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		e.oldIdx += prefix
+		e.newIdx += prefix
+		edits = append(edits, e)
+	}
+	for i := suffix; i > 0; i-- {
+		edits = append(edits, diffEdit{kind: ' ', oldIdx: len(a) - i, newIdx: len(b) - i})
+	}
+	return edits
+}
+
+// myersDiff sucht die kürzeste Edit-Folge (Myers 1986). Für jede Runde d
+// wird der Zustand der Diagonalen -d-1..d+1 gespeichert, um den Weg
+// anschließend zurückzuverfolgen.
+func myersDiff(a, b []string) []diffEdit {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	limit := min(n+m, diffMaxEdits)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	found := false
+	for d := 0; d <= limit && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Einfügen
+			} else {
+				x = v[offset+k-1] + 1 // Löschen
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	if !found {
+		// Zu viele Änderungen: alles löschen, alles einfügen
+		edits := make([]diffEdit, 0, n+m)
+		for i := 0; i < n; i++ {
+			edits = append(edits, diffEdit{kind: '-', oldIdx: i})
+		}
+		for j := 0; j < m; j++ {
+			edits = append(edits, diffEdit{kind: '+', newIdx: j})
+		}
+		return edits
+	}
+
+	// Rückwärts vom Ziel zum Anfang
+	var reversed []diffEdit
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d+1] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, diffEdit{kind: ' ', oldIdx: x, newIdx: y})
+		}
+		if x == prevX {
+			reversed = append(reversed, diffEdit{kind: '+', newIdx: prevY})
+		} else {
+			reversed = append(reversed, diffEdit{kind: '-', oldIdx: prevX})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, diffEdit{kind: ' ', oldIdx: x, newIdx: y})
+	}
+
+	edits := make([]diffEdit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
+
+// buildHunks fasst Änderungen mit diffContextLines Zeilen Kontext zu Hunks
+// zusammen. Liegen zwei Änderungen nah beieinander, entsteht ein Hunk.
+func buildHunks(edits []diffEdit, a, b []string) []DiffHunk {
+	hunks := []DiffHunk{}
+
+	i := 0
+	for i < len(edits) {
+		// Nächste Änderung suchen
+		for i < len(edits) && edits[i].kind == ' ' {
+			i++
+		}
+		if i >= len(edits) {
+			break
+		}
+
+		// Der Kontext überlappt nie mit dem vorherigen Hunk, da dieser erst
+		// bei mehr als 2*diffContextLines unveränderten Zeilen endet
+		start := max(i-diffContextLines, 0)
+
+		// Ende des Hunks: nach der letzten Änderung, auf die höchstens
+		// 2*diffContextLines unveränderte Zeilen folgen
+		end := i
+		for end < len(edits) {
+			if edits[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].kind == ' ' {
+				run++
+			}
+			if run >= len(edits) || run-end > 2*diffContextLines {
+				end = min(end+diffContextLines, len(edits))
+				break
+			}
+			end = run
+		}
+
+		hunks = append(hunks, newHunk(edits[start:end], a, b))
+		i = end
+	}
+	return hunks
+}
+
+// newHunk erstellt einen Hunk aus einem Ausschnitt der Edit-Folge.
+func newHunk(edits []diffEdit, a, b []string) DiffHunk {
+	hunk := DiffHunk{Lines: make([]DiffLine, 0, len(edits))}
+	for _, e := range edits {
+		line := DiffLine{Kind: string(e.kind)}
+		switch e.kind {
+		case ' ':
+			line.Text, line.OldLine, line.NewLine = a[e.oldIdx], e.oldIdx+1, e.newIdx+1
+		case '-':
+			line.Text, line.OldLine = a[e.oldIdx], e.oldIdx+1
+		case '+':
+			line.Text, line.NewLine = b[e.newIdx], e.newIdx+1
+		}
+
+		if line.OldLine > 0 {
+			if hunk.OldStart == 0 {
+				hunk.OldStart = line.OldLine
+			}
+			hunk.OldLines++
+		}
+		if line.NewLine > 0 {
+			if hunk.NewStart == 0 {
+				hunk.NewStart = line.NewLine
+			}
+			hunk.NewLines++
+		}
+		hunk.Lines = append(hunk.Lines, line)
+	}
+	return hunk
+}
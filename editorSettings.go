@@ -0,0 +1,283 @@
+// editorSettings.go — Editor-Einstellungen in Ebenen.
+// Die wirksamen Einstellungen für eine Datei ergeben sich aus (spätere
+// Ebenen überschreiben frühere, nur gesetzte Felder zählen):
+//   1. Standardwerte (defaultEditorSettings)
+//   2. globale Einstellungen (AppConfig: editor_font, editor, ...)
+//   3. "editor" in der .leoedit.json des Projekts
+//   4. Sprach-Einstellungen: erst global (AppConfig languages), dann
+//      "languages" der .leoedit.json
+//
+// Sprach-Schlüssel sind die Dateitypen des Frontends (siehe getFileType()
+// in frontend/src/lib/utils.js, z.B. "go", "python", "javascript") oder
+// eine Endung mit Punkt (z.B. ".ts"), die nach dem Dateityp gilt:
+//
+//	"editor":    {"tabSize": 2, "insertSpaces": true},
+//	"languages": {"go": {"insertSpaces": false}, ".md": {"trimTrailingWhitespace": false}}
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.GetEffectiveSettings(path)             → EditorSettings
+//   window.go.main.App.SetGlobalEditorOverrides(overrides)
+//   window.go.main.App.SetLanguageEditorOverrides(language, overrides)
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxEditorTabSize = 16
+
+// EditorOverrides ist eine Ebene der Editor-Einstellungen.
+// Nicht gesetzte Felder (nil) übernehmen den Wert der Ebene darunter.
+type EditorOverrides struct {
+	Font                   *string `json:"font,omitempty"`
+	FontSize               *int    `json:"fontSize,omitempty"`
+	TabSize                *int    `json:"tabSize,omitempty"`      // Einrückungsbreite
+	InsertSpaces           *bool   `json:"insertSpaces,omitempty"` // false = Tabs
+	TrimTrailingWhitespace *bool   `json:"trimTrailingWhitespace,omitempty"`
+	InsertFinalNewline     *bool   `json:"insertFinalNewline,omitempty"`
+	LineEnding             *string `json:"lineEnding,omitempty"` // wie TextFormat.LineEnding, "" = wie Datei
+
+	Extra map[string]json.RawMessage `json:"-"` // Unbekannte Schlüssel (siehe projectSchema.go)
+}
+
+// defaultEditorSettings sind die Standardwerte (Ebene 1).
+var defaultEditorSettings = EditorSettings{
+	Font:                   "JetBrains Mono, monospace",
+	FontSize:               14,
+	TabSize:                4,
+	InsertSpaces:           true,
+	TrimTrailingWhitespace: false,
+	InsertFinalNewline:     true,
+	LineEnding:             "", // Zeilenenden der Datei beibehalten
+	Language:               "text",
+}
+
+// editorLanguages ordnet Endungen den Dateitypen des Frontends zu.
+// Muss mit getFileType() in frontend/src/lib/utils.js übereinstimmen
+// (nur Textformate).
+var editorLanguages = map[string]string{
+	"js": "javascript", "jsx": "javascript", "ts": "javascript", "tsx": "javascript",
+	"mjs": "javascript", "cjs": "javascript",
+	"html": "html", "htm": "html", "xhtml": "html",
+	"css": "css", "scss": "css", "sass": "css", "less": "css",
+	"json": "json", "json5": "json",
+	"xml": "xml", "yaml": "yaml", "yml": "yaml", "toml": "toml",
+	"ini": "ini", "cfg": "ini", "conf": "ini",
+	"py": "python", "pyw": "python",
+	"md": "markdown", "markdown": "markdown", "mdown": "markdown",
+	"txt": "text", "log": "text",
+	"go": "go", "java": "java",
+	"cpp": "cpp", "vala": "cpp", "cc": "cpp", "cxx": "cpp", "c++": "cpp", "hpp": "cpp", "hxx": "cpp",
+	"c": "c", "h": "c",
+	"cs": "csharp", "php": "php", "php3": "php", "php4": "php", "php5": "php", "phtml": "php",
+	"rb": "ruby", "swift": "swift", "kt": "kotlin", "kts": "kotlin", "rs": "rust", "lua": "lua",
+	"pl": "perl", "pm": "perl",
+	"sh": "bash", "bash": "bash", "zsh": "bash", "fish": "bash",
+	"bat": "batch", "cmd": "batch", "ps1": "powershell",
+	"sql": "sql", "r": "r", "m": "matlab", "f": "fortran", "f90": "fortran", "f95": "fortran",
+}
+
+// GetEffectiveSettings liefert die wirksamen Editor-Einstellungen für
+// path. Das Projekt wird über die nächste .leoedit.json oberhalb von path
+// gefunden; ohne path gelten nur Standardwerte und globale Einstellungen.
+// Eine fehlerhafte .leoedit.json wird übergangen.
+func (a *App) GetEffectiveSettings(path string) EditorSettings {
+	settings := defaultEditorSettings
+	settings.applyOverrides(a.globalEditorOverrides())
+
+	var project *ProjectConfig
+	if path != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+			settings.Language = editorLanguage(path)
+			if root := findProjectConfigRoot(filepath.Dir(path)); root != "" {
+				if config, err := readProjectConfig(root); err == nil {
+					project = config
+					settings.ProjectRoot = root
+				}
+			}
+		}
+	}
+	if project != nil && project.Editor != nil {
+		settings.applyOverrides(*project.Editor)
+	}
+
+	// Sprach-Einstellungen: Dateityp vor Endung, global vor Projekt
+	if path != "" {
+		keys := []string{settings.Language}
+		if ext := strings.ToLower(filepath.Ext(path)); ext != "" {
+			keys = append(keys, ext)
+		}
+		for _, key := range keys {
+			if overrides, ok := a.Config.Languages[key]; ok {
+				settings.applyOverrides(overrides)
+			}
+			if project != nil {
+				if overrides, ok := project.Languages[key]; ok {
+					settings.applyOverrides(overrides)
+				}
+			}
+		}
+	}
+	return settings
+}
+
+// SetGlobalEditorOverrides speichert die globalen Einstellungen (Ebene 2).
+// Font und FontSize werden wie bei SetEditorSettings gespeichert.
+func (a *App) SetGlobalEditorOverrides(overrides EditorOverrides) error {
+	if err := checkEditorOverrides(overrides); err != nil {
+		return err
+	}
+	if overrides.Font != nil {
+		a.Config.EditorFont = *overrides.Font
+		overrides.Font = nil
+	}
+	if overrides.FontSize != nil {
+		a.Config.EditorFontSize = *overrides.FontSize
+		overrides.FontSize = nil
+	}
+	a.Config.Editor = overrides
+	return a.saveConfig()
+}
+
+// SetLanguageEditorOverrides speichert globale Einstellungen für einen
+// Dateityp oder eine Endung. Ohne gesetzte Felder wird der Eintrag entfernt.
+func (a *App) SetLanguageEditorOverrides(language string, overrides EditorOverrides) error {
+	language = strings.TrimSpace(language)
+	if language == "" {
+		return fmt.Errorf("Sprache darf nicht leer sein")
+	}
+	if err := checkEditorOverrides(overrides); err != nil {
+		return err
+	}
+
+	if overrides.isEmpty() {
+		delete(a.Config.Languages, language)
+	} else {
+		if a.Config.Languages == nil {
+			a.Config.Languages = make(map[string]EditorOverrides)
+		}
+		a.Config.Languages[language] = overrides
+	}
+	return a.saveConfig()
+}
+
+// isEmpty prüft, ob die Ebene keine Felder setzt.
+func (o EditorOverrides) isEmpty() bool {
+	return o.Font == nil && o.FontSize == nil && o.TabSize == nil && o.InsertSpaces == nil &&
+		o.TrimTrailingWhitespace == nil && o.InsertFinalNewline == nil && o.LineEnding == nil &&
+		len(o.Extra) == 0
+}
+
+// globalEditorOverrides fasst die globalen Einstellungen als Ebene zusammen.
+func (a *App) globalEditorOverrides() EditorOverrides {
+	overrides := a.Config.Editor
+	if a.Config.EditorFont != "" {
+		overrides.Font = &a.Config.EditorFont
+	}
+	if a.Config.EditorFontSize > 0 {
+		overrides.FontSize = &a.Config.EditorFontSize
+	}
+	return overrides
+}
+
+// applyOverrides übernimmt alle gesetzten und gültigen Felder einer Ebene.
+// Ungültige Werte (z.B. aus einer von Hand bearbeiteten Datei) werden
+// übergangen, statt die Ebene darunter zu verdecken.
+func (s *EditorSettings) applyOverrides(o EditorOverrides) {
+	if o.Font != nil && strings.TrimSpace(*o.Font) != "" {
+		s.Font = *o.Font
+	}
+	if o.FontSize != nil && *o.FontSize > 0 {
+		s.FontSize = *o.FontSize
+	}
+	if o.TabSize != nil && *o.TabSize >= 1 && *o.TabSize <= maxEditorTabSize {
+		s.TabSize = *o.TabSize
+	}
+	if o.InsertSpaces != nil {
+		s.InsertSpaces = *o.InsertSpaces
+	}
+	if o.TrimTrailingWhitespace != nil {
+		s.TrimTrailingWhitespace = *o.TrimTrailingWhitespace
+	}
+	if o.InsertFinalNewline != nil {
+		s.InsertFinalNewline = *o.InsertFinalNewline
+	}
+	if o.LineEnding != nil {
+		if ending, ok := normalizeLineEnding(*o.LineEnding); ok {
+			s.LineEnding = ending
+		}
+	}
+}
+
+// checkEditorOverrides prüft eine Ebene und meldet das erste ungültige Feld.
+func checkEditorOverrides(o EditorOverrides) error {
+	if issues := editorOverrideIssues("", o); len(issues) > 0 {
+		return fmt.Errorf("%s: %s", issues[0].Field, issues[0].Message)
+	}
+	return nil
+}
+
+// editorOverrideIssues prüft eine Ebene; field ist der Pfad der Ebene
+// für die Meldungen (z.B. "languages.go").
+func editorOverrideIssues(field string, o EditorOverrides) []ProjectConfigIssue {
+	prefix := field
+	if prefix != "" {
+		prefix += "."
+	}
+
+	var issues []ProjectConfigIssue
+	if o.FontSize != nil && *o.FontSize <= 0 {
+		issues = append(issues, ProjectConfigIssue{Field: prefix + "fontSize", Message: "Schriftgröße muss größer als 0 sein"})
+	}
+	if o.TabSize != nil && (*o.TabSize < 1 || *o.TabSize > maxEditorTabSize) {
+		issues = append(issues, ProjectConfigIssue{Field: prefix + "tabSize", Message: fmt.Sprintf("Einrückung muss zwischen 1 und %d liegen", maxEditorTabSize)})
+	}
+	if o.LineEnding != nil {
+		if _, ok := normalizeLineEnding(*o.LineEnding); !ok {
+			issues = append(issues, ProjectConfigIssue{Field: prefix + "lineEnding", Message: fmt.Sprintf("Unbekanntes Zeilenende %q (lf, crlf, cr oder leer)", *o.LineEnding)})
+		}
+	}
+	return issues
+}
+
+// normalizeLineEnding bildet Schreibweisen wie "LF" oder "auto" auf die
+// Werte von TextFormat.LineEnding ab ("" = Zeilenenden der Datei).
+func normalizeLineEnding(ending string) (string, bool) {
+	switch ending = strings.ToLower(strings.TrimSpace(ending)); ending {
+	case "", "auto":
+		return "", true
+	case LineEndingLF, LineEndingCRLF, LineEndingCR:
+		return ending, true
+	}
+	return "", false
+}
+
+// editorLanguage bestimmt den Dateityp eines Pfades ("text" wenn unbekannt).
+func editorLanguage(path string) string {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if language, ok := editorLanguages[ext]; ok {
+		return language
+	}
+	return "text"
+}
+
+// findProjectConfigRoot sucht ab dir aufwärts nach einer .leoedit.json
+// und gibt deren Ordner zurück (leer, wenn keine gefunden wird).
+func findProjectConfigRoot(dir string) string {
+	dir = filepath.Clean(dir)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, projectConfigFile)); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
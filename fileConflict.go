@@ -0,0 +1,173 @@
+// fileConflict.go — Speichern mit Konflikterkennung.
+// Beim Lesen (ReadTextFile, LoadFile) wird ein Fingerabdruck der Datei
+// mitgeliefert. Beim Speichern übergibt das Frontend diesen Fingerabdruck;
+// hat ein anderes Programm die Datei inzwischen geändert, wird nicht
+// geschrieben, sondern ein Konflikt samt aktuellem Inhalt zurückgegeben:
+//   window.go.main.App.SaveFileChecked(content, filename, fingerprint)
+//
+// Das Frontend kann dann überschreiben (erneut mit Conflict.Disk oder ohne
+// Fingerabdruck speichern), neu laden oder zusammenführen.
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileFingerprint beschreibt den Zustand einer Datei auf der Festplatte.
+// ModTime und Size werden zuerst verglichen; unterscheiden sie sich, entscheidet
+// der Hash (z.B. ändert "touch" oder ein git checkout nur die Änderungszeit).
+type FileFingerprint struct {
+	ModTime string `json:"modTime"` // RFC3339Nano
+	Size    int64  `json:"size"`
+	Hash    string `json:"hash"` // SHA-256 (hex) des Inhalts
+}
+
+// SaveConflict beschreibt eine Datei, die seit dem Laden verändert wurde.
+type SaveConflict struct {
+	Path        string          `json:"path"`
+	Disk        FileFingerprint `json:"disk"`        // Aktueller Zustand auf der Festplatte
+	DiskContent string          `json:"diskContent"` // Aktueller Inhalt (leer bei Deleted)
+	Deleted     bool            `json:"deleted"`     // Datei existiert nicht mehr
+}
+
+// SaveConflictError wird von saveFileChecked zurückgegeben, wenn die Datei
+// nicht dem erwarteten Fingerabdruck entspricht.
+type SaveConflictError struct {
+	Conflict SaveConflict
+}
+
+func (e *SaveConflictError) Error() string {
+	if e.Conflict.Deleted {
+		return fmt.Sprintf("Datei wurde inzwischen gelöscht: %s", e.Conflict.Path)
+	}
+	return fmt.Sprintf("Datei wurde inzwischen von einem anderen Programm geändert: %s", e.Conflict.Path)
+}
+
+// SaveFileChecked speichert eine Datei nur, wenn sie auf der Festplatte noch
+// dem Fingerabdruck expected entspricht. Ist expected.Hash leer, wird ohne
+// Prüfung gespeichert (z.B. "Trotzdem überschreiben").
+// Bei einem Konflikt ist Success false und Conflict gesetzt.
+func (a *App) SaveFileChecked(content, filename string, expected FileFingerprint) SaveResult {
+	return a.SaveFileWithFormat(content, filename, TextFormat{}, expected)
+}
+
+// SaveFileWithFormat speichert wie SaveFileChecked und wandelt dabei in das
+// gewünschte Format um (z.B. Latin-1 → UTF-8 oder CRLF → LF). Leere Felder
+// in format behalten das Format der Datei bei (siehe encodeForSave).
+func (a *App) SaveFileWithFormat(content, filename string, format TextFormat, expected FileFingerprint) SaveResult {
+	fingerprint, err := saveFileChecked(filename, content, format, expected)
+	if err != nil {
+		var conflict *SaveConflictError
+		if errors.As(err, &conflict) {
+			return SaveResult{Success: false, Path: filename, Message: err.Error(), Conflict: &conflict.Conflict}
+		}
+		return SaveResult{Success: false, Path: filename, Message: err.Error()}
+	}
+
+	return SaveResult{
+		Success:     true,
+		Path:        filename,
+		Title:       filepath.Base(filename),
+		Fingerprint: fingerprint,
+	}
+}
+
+// saveFileChecked prüft den Fingerabdruck, kodiert content und schreibt die
+// Datei atomar. Gibt den Fingerabdruck der neu geschriebenen Datei zurück.
+func saveFileChecked(filename, content string, format TextFormat, expected FileFingerprint) (FileFingerprint, error) {
+	if filename == "" {
+		return FileFingerprint{}, fmt.Errorf("Dateiname darf nicht leer sein")
+	}
+	filename, err := guardProjectPath(filename, true)
+	if err != nil {
+		return FileFingerprint{}, err
+	}
+	if _, err := os.Stat(filepath.Dir(filename)); os.IsNotExist(err) {
+		return FileFingerprint{}, fmt.Errorf("Ordner Schreiben fehlgeschlagen: %w", err)
+	}
+
+	if expected.Hash != "" {
+		if err := checkFingerprint(filename, expected); err != nil {
+			return FileFingerprint{}, err
+		}
+	}
+
+	data, err := encodeForSave(filename, content, format)
+	if err != nil {
+		return FileFingerprint{}, err
+	}
+	recordFileHistory(filename)
+	if err := writeFileAtomic(filename, data); err != nil {
+		return FileFingerprint{}, err
+	}
+	markSearchIndexDirty(filename)
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return FileFingerprint{}, fmt.Errorf("Datei nach dem Speichern nicht lesbar: %w", err)
+	}
+	return newFingerprint(info, data), nil
+}
+
+// checkFingerprint vergleicht die Datei auf der Festplatte mit expected.
+// Gibt einen *SaveConflictError zurück, wenn sie sich unterscheiden.
+func checkFingerprint(filename string, expected FileFingerprint) error {
+	// Schneller Weg: unveränderte Änderungszeit und Größe
+	if info, err := os.Stat(filename); err == nil &&
+		info.ModTime().Format(time.RFC3339Nano) == expected.ModTime && info.Size() == expected.Size {
+		return nil
+	}
+
+	data, current, err := readFileWithFingerprint(filename)
+	if os.IsNotExist(err) {
+		return &SaveConflictError{Conflict: SaveConflict{Path: filename, Deleted: true}}
+	}
+	if err != nil {
+		return fmt.Errorf("Fehler beim Lesen: %w", err)
+	}
+
+	if current.Hash == expected.Hash {
+		return nil
+	}
+	diskContent, _ := decodeTextFile(data)
+	return &SaveConflictError{Conflict: SaveConflict{
+		Path:        filename,
+		Disk:        current,
+		DiskContent: diskContent,
+	}}
+}
+
+// readFileWithFingerprint liest eine Datei und berechnet ihren Fingerabdruck.
+// Änderungszeit und Größe stammen vom selben geöffneten Dateihandle.
+func readFileWithFingerprint(filename string) ([]byte, FileFingerprint, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, FileFingerprint{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, FileFingerprint{}, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, FileFingerprint{}, err
+	}
+	return data, newFingerprint(info, data), nil
+}
+
+// newFingerprint erstellt einen Fingerabdruck aus Dateiinfo und Inhalt.
+func newFingerprint(info os.FileInfo, data []byte) FileFingerprint {
+	return FileFingerprint{
+		ModTime: info.ModTime().Format(time.RFC3339Nano),
+		Size:    int64(len(data)),
+		Hash:    fmt.Sprintf("%x", sha256.Sum256(data)),
+	}
+}
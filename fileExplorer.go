@@ -1,7 +1,8 @@
 // fileExplorer.go — Verzeichnis-Listing für den Datei-Explorer.
 // Wird vom Frontend über Wails-Bindings aufgerufen:
-//   window.go.main.App.ListDirectory(path) → Verzeichnisinhalt
-//   window.go.main.App.GetHomeDirectory()  → Home-Verzeichnis
+//   window.go.main.App.ListDirectory(path)                     → Verzeichnisinhalt
+//   window.go.main.App.ListDirectoryWithOptions(path, options) → mit Sortierung
+//   window.go.main.App.GetHomeDirectory()                      → Home-Verzeichnis
 package main
 
 import (
@@ -10,15 +11,53 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
+)
+
+// Werte für ListOptions.SortBy
+const (
+	SortByName  = "name"
+	SortBySize  = "size"
+	SortByMtime = "mtime"
+	SortByType  = "type" // Nach Endung, dann Name
 )
 
 // FileEntry beschreibt eine einzelne Datei oder einen Ordner.
+// Bei Symlinks beschreiben IsDirectory, Size und ModTime das Ziel;
+// BrokenSymlink ist gesetzt, wenn das Ziel nicht existiert.
+// ChildCount ist nur bei Ordnern und mit ListOptions.CountChildren gesetzt
+// (-1, wenn nicht lesbar).
 type FileEntry struct {
-	Name        string `json:"name"`
-	Path        string `json:"path"`
-	IsDirectory bool   `json:"isDirectory"`
-	Size        int64  `json:"size"`
-	Extension   string `json:"extension"`
+	Name          string `json:"name"`
+	Path          string `json:"path"`
+	IsDirectory   bool   `json:"isDirectory"`
+	Size          int64  `json:"size"`
+	Extension     string `json:"extension"`
+	ModTime       string `json:"modTime"`     // RFC3339
+	Permissions   string `json:"permissions"` // z.B. "rwxr-xr-x"
+	Mode          uint32 `json:"mode"`        // Rechte als Zahl, z.B. 0755
+	Owner         string `json:"owner"`
+	Executable    bool   `json:"executable"`
+	IsSymlink     bool   `json:"isSymlink"`
+	SymlinkTarget string `json:"symlinkTarget"`
+	BrokenSymlink bool   `json:"brokenSymlink"`
+	External      bool   `json:"external"` // Symlink zeigt aus dem Projekt heraus
+	ChildCount    int    `json:"childCount"`
+
+	modTime int64 // UnixNano, für die Sortierung nach Änderungszeit
+}
+
+// ListOptions steuert die Sortierung von ListDirectoryWithOptions.
+// Ohne Angaben: nach Name aufsteigend, Ordner zuerst.
+// Natural vergleicht Zahlen im Namen numerisch ("file2" vor "file10").
+// CountChildren füllt FileEntry.ChildCount; dafür wird jeder Unterordner
+// gelesen, was bei großen Ordnern spürbar dauert.
+type ListOptions struct {
+	SortBy        string `json:"sortBy"`
+	Descending    bool   `json:"descending"`
+	Natural       bool   `json:"natural"`
+	FoldersMixed  bool   `json:"foldersMixed"` // Ordner nicht vorziehen
+	CountChildren bool   `json:"countChildren"`
 }
 
 // DirectoryResult ist das Ergebnis von ListDirectory.
@@ -30,9 +69,23 @@ type DirectoryResult struct {
 }
 
 // ListDirectory liest den Inhalt eines Verzeichnisses.
-// Versteckte Dateien (mit Punkt am Anfang) werden übersprungen.
+// Versteckte Dateien (mit Punkt am Anfang) werden übersprungen, sofern
+// ShowHiddenFiles nicht gesetzt ist (siehe explorerVisibility).
 // Sortierung: Ordner zuerst, dann Dateien — jeweils alphabetisch.
 func (a *App) ListDirectory(path string) DirectoryResult {
+	return a.ListDirectoryWithOptions(path, ListOptions{})
+}
+
+// ListDirectoryWithOptions liest den Inhalt eines Verzeichnisses und
+// sortiert ihn nach options.
+func (a *App) ListDirectoryWithOptions(path string, options ListOptions) DirectoryResult {
+	return a.listDirectory(path, options, "")
+}
+
+// listDirectory liest ein Verzeichnis für Datei- und Project Explorer.
+// Ist projectRoot gesetzt, gelten zusätzlich .gitignore und include/exclude
+// des Projekts.
+func (a *App) listDirectory(path string, options ListOptions, projectRoot string) DirectoryResult {
 	if path == "" {
 		path = a.GetHomeDirectory()
 	}
@@ -43,50 +96,40 @@ func (a *App) ListDirectory(path string) DirectoryResult {
 		return DirectoryResult{Error: "Ungültiger Pfad: " + err.Error()}
 	}
 
+	switch options.SortBy {
+	case "", SortByName, SortBySize, SortByMtime, SortByType:
+	default:
+		return DirectoryResult{Error: "Unbekannte Sortierung: " + options.SortBy}
+	}
+
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		return DirectoryResult{Error: "Verzeichnis konnte nicht gelesen werden: " + err.Error()}
 	}
 
-	var fileEntries []FileEntry
-	for _, entry := range entries {
-		name := entry.Name()
+	visibility := a.newExplorerVisibility(path, projectRoot)
 
-		// Versteckte Dateien überspringen
-		if strings.HasPrefix(name, ".") {
+	fileEntries := []FileEntry{}
+	for _, entry := range entries {
+		fullPath := filepath.Join(path, entry.Name())
+		if !visibility.isVisible(fullPath, entry.IsDir()) {
 			continue
 		}
 
-		fullPath := filepath.Join(path, name)
-		info, err := entry.Info()
+		fe, err := newFileEntry(fullPath)
 		if err != nil {
 			continue
 		}
-
-		fe := FileEntry{
-			Name:        name,
-			Path:        fullPath,
-			IsDirectory: entry.IsDir(),
-			Size:        info.Size(),
+		if fe.IsSymlink && projectRoot != "" {
+			fe.External = isExternalSymlink(fullPath, projectRoot)
 		}
-
-		if !entry.IsDir() {
-			ext := filepath.Ext(name)
-			if ext != "" {
-				fe.Extension = strings.TrimPrefix(ext, ".")
-			}
+		if fe.IsDirectory && options.CountChildren {
+			fe.ChildCount = countDirEntries(fullPath)
 		}
-
 		fileEntries = append(fileEntries, fe)
 	}
 
-	// Sortierung: Ordner zuerst, dann Dateien, jeweils alphabetisch (case-insensitive)
-	sort.Slice(fileEntries, func(i, j int) bool {
-		if fileEntries[i].IsDirectory != fileEntries[j].IsDirectory {
-			return fileEntries[i].IsDirectory
-		}
-		return strings.ToLower(fileEntries[i].Name) < strings.ToLower(fileEntries[j].Name)
-	})
+	sortFileEntries(fileEntries, options)
 
 	parent := filepath.Dir(path)
 	if parent == path {
@@ -100,6 +143,196 @@ func (a *App) ListDirectory(path string) DirectoryResult {
 	}
 }
 
+// explorerVisibility entscheidet, welche Einträge der Explorer anzeigt.
+// Vorrang in dieser Reihenfolge:
+//  1. show-Muster der .leoedit.json → immer sichtbar
+//  2. hide-Muster der .leoedit.json → ausgeblendet
+//  3. Punkt am Anfang → ausgeblendet, außer AppConfig.ShowHiddenFiles
+//  4. .gitignore und include/exclude (nur im Project Explorer)
+type explorerVisibility struct {
+	showHidden bool
+	root       string // Projektstamm für hide/show (leer: keine Projektregeln)
+	hide       []ignorePattern
+	show       []ignorePattern
+	filter     *pathFilter
+}
+
+// newExplorerVisibility lädt die Regeln für ein Listing von path. Ohne
+// projectRoot wird das Projekt über findFilterRoot gesucht.
+func (a *App) newExplorerVisibility(path, projectRoot string) *explorerVisibility {
+	v := &explorerVisibility{showHidden: a.Config.ShowHiddenFiles}
+
+	root := projectRoot
+	if root == "" {
+		root = findFilterRoot(path)
+	} else {
+		v.filter = newProjectPathFilter(projectRoot, nil)
+	}
+	if config, err := readProjectConfig(root); err == nil {
+		v.root = root
+		v.hide = parseIgnorePatterns(config.Hide)
+		v.show = parseIgnorePatterns(config.Show)
+	}
+	return v
+}
+
+// isVisible prüft einen Eintrag anhand der Regeln.
+func (v *explorerVisibility) isVisible(path string, isDir bool) bool {
+	if v.root != "" {
+		rel, err := filepath.Rel(v.root, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			rel = filepath.ToSlash(rel)
+			if matchIgnorePatterns(v.show, rel, isDir) {
+				return true
+			}
+			if matchIgnorePatterns(v.hide, rel, isDir) {
+				return false
+			}
+		}
+	}
+
+	if !v.showHidden && strings.HasPrefix(filepath.Base(path), ".") {
+		return false
+	}
+	return v.filter == nil || !v.filter.isHidden(path, isDir)
+}
+
+// newFileEntry liest die Metadaten einer Datei. Symlinks werden aufgelöst,
+// defekte Symlinks erscheinen als Datei mit BrokenSymlink.
+func newFileEntry(fullPath string) (FileEntry, error) {
+	linfo, err := os.Lstat(fullPath)
+	if err != nil {
+		return FileEntry{}, err
+	}
+
+	fe := FileEntry{
+		Name: filepath.Base(fullPath),
+		Path: fullPath,
+	}
+
+	info := linfo
+	if linfo.Mode()&os.ModeSymlink != 0 {
+		fe.IsSymlink = true
+		fe.SymlinkTarget, _ = os.Readlink(fullPath)
+		if target, err := os.Stat(fullPath); err == nil {
+			info = target
+		} else {
+			fe.BrokenSymlink = true
+		}
+	}
+
+	fe.IsDirectory = info.IsDir()
+	fe.Size = info.Size()
+	fe.ModTime = info.ModTime().Format(time.RFC3339)
+	fe.modTime = info.ModTime().UnixNano()
+	fe.Mode = uint32(info.Mode().Perm())
+	fe.Permissions = info.Mode().Perm().String()[1:]
+	fe.Owner = fileOwner(info)
+
+	if !fe.IsDirectory {
+		fe.Executable = info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+		if ext := filepath.Ext(fe.Name); ext != "" {
+			fe.Extension = strings.TrimPrefix(ext, ".")
+		}
+	}
+	return fe, nil
+}
+
+// countDirEntries zählt die Einträge eines Ordners, ohne sie einzeln zu prüfen.
+func countDirEntries(path string) int {
+	dir, err := os.Open(path)
+	if err != nil {
+		return -1
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return -1
+	}
+	return len(names)
+}
+
+// sortFileEntries sortiert nach options. Bei Gleichstand entscheidet der
+// Name, damit die Reihenfolge stabil bleibt.
+func sortFileEntries(entries []FileEntry, options ListOptions) {
+	nameLess := func(a, b string) bool {
+		a, b = strings.ToLower(a), strings.ToLower(b)
+		if options.Natural {
+			return naturalLess(a, b)
+		}
+		return a < b
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !options.FoldersMixed && a.IsDirectory != b.IsDirectory {
+			return a.IsDirectory
+		}
+		if options.Descending {
+			a, b = b, a
+		}
+
+		switch options.SortBy {
+		case SortBySize:
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case SortByMtime:
+			if a.modTime != b.modTime {
+				return a.modTime < b.modTime
+			}
+		case SortByType:
+			extA, extB := strings.ToLower(a.Extension), strings.ToLower(b.Extension)
+			if extA != extB {
+				return extA < extB
+			}
+		}
+		return nameLess(a.Name, b.Name)
+	})
+}
+
+// naturalLess vergleicht Zeichenketten mit Zahlen als Zahlen:
+// "file2" < "file10". Führende Nullen zählen nur bei sonst gleichen Zahlen.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			numA, restA := splitDigits(a)
+			numB, restB := splitDigits(b)
+			trimA, trimB := strings.TrimLeft(numA, "0"), strings.TrimLeft(numB, "0")
+			if len(trimA) != len(trimB) {
+				return len(trimA) < len(trimB)
+			}
+			if trimA != trimB {
+				return trimA < trimB
+			}
+			if len(numA) != len(numB) {
+				return len(numA) < len(numB)
+			}
+			a, b = restA, restB
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// splitDigits trennt die führenden Ziffern von s ab.
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
 // GetHomeDirectory gibt das Home-Verzeichnis des Benutzers zurück.
 func (a *App) GetHomeDirectory() string {
 	home, err := os.UserHomeDir()
@@ -115,6 +348,21 @@ func (a *App) RenameFile(oldPath, newPath string) error {
 		return fmt.Errorf("Pfad darf nicht leer sein")
 	}
 
+	// Im Projekt: Symlinks werden selbst umbenannt, nicht ihr Ziel, und
+	// Einträge des Projekts bleiben im Projekt
+	oldPath, err := guardProjectPath(oldPath, false)
+	if err != nil {
+		return err
+	}
+	if root := currentProjectRoot(); root != "" && pathWithinRoot(oldPath, root, caseInsensitiveFS) {
+		newPath, err = resolveProjectPath(newPath, root, false)
+	} else {
+		newPath, err = guardProjectPath(newPath, false)
+	}
+	if err != nil {
+		return err
+	}
+
 	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
 		return fmt.Errorf("Quelle existiert nicht: %s", oldPath)
 	}
@@ -127,33 +375,10 @@ func (a *App) RenameFile(oldPath, newPath string) error {
 		return fmt.Errorf("Umbenennen fehlgeschlagen: %w", err)
 	}
 
-	return nil
-}
-
-// DeleteFile löscht eine Datei oder einen Ordner (inkl. Inhalt).
-func (a *App) DeleteFile(path string) error {
-	if path == "" {
-		return fmt.Errorf("Pfad darf nicht leer sein")
-	}
-
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return fmt.Errorf("Ungültiger Pfad: %w", err)
-	}
-
-	// Sicherheitscheck: Root- und Home-Verzeichnis nicht löschen
-	home := a.GetHomeDirectory()
-	if absPath == "/" || absPath == home {
-		return fmt.Errorf("Dieses Verzeichnis darf nicht gelöscht werden")
-	}
-
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return fmt.Errorf("Datei existiert nicht: %s", absPath)
-	}
-
-	if err := os.RemoveAll(absPath); err != nil {
-		return fmt.Errorf("Löschen fehlgeschlagen: %w", err)
-	}
-
+	oldAbs, _ := filepath.Abs(oldPath)
+	newAbs, _ := filepath.Abs(newPath)
+	recordFileOperation(FileOpRename,
+		fmt.Sprintf("Umbenennen: %s → %s", filepath.Base(oldAbs), filepath.Base(newAbs)),
+		[]string{oldAbs, newAbs}, renameStep(oldAbs, newAbs))
 	return nil
 }
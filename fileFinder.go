@@ -0,0 +1,446 @@
+// fileFinder.go — Schnelles Öffnen von Dateien ("Go to File").
+// Bewertet alle Dateien eines Projekts gegen eine unscharfe Eingabe
+// (z.B. "clsfe" → "frontend/src/clsFileExplorer.js"):
+//   - alle Zeichen der Eingabe müssen in dieser Reihenfolge im Pfad vorkommen
+//   - Treffer am Anfang von Pfadsegmenten, Wörtern und camelCase-Grenzen
+//     sowie zusammenhängende Treffer zählen mehr
+//   - Treffer im Dateinamen zählen mehr als im Ordnerpfad
+//   - kürzlich geöffnete Dateien (AppConfig.RecentFiles) werden bevorzugt
+//
+// Die Dateiliste eines Projekts wird kurz zwischengespeichert, damit
+// FindFiles bei jedem Tastendruck aufgerufen werden kann.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf16"
+)
+
+const (
+	defaultFileFinderLimit = 50
+	fileListCacheTTL       = 10 * time.Second
+	maxFileFinderFiles     = 200000
+)
+
+// Punkte für die Bewertung eines Treffers
+const (
+	scoreMatch          = 16
+	scoreSegmentStart   = 24 // Zeichen direkt nach "/" oder am Pfadanfang
+	scoreWordStart      = 12 // Zeichen nach "_", "-", ".", " "
+	scoreCamelCase      = 10 // Großbuchstabe nach Kleinbuchstabe
+	scoreConsecutive    = 14 // Direkt auf den vorigen Treffer folgend
+	scoreBasename       = 8  // Treffer im Dateinamen
+	scoreGapPenalty     = 1  // Pro übersprungenem Zeichen
+	scoreRecentMax      = 60 // Bonus für die zuletzt geöffnete Datei
+	scoreRecentDecrease = 5  // Abzug je Platz in der Recent-Liste
+)
+
+// FileMatch ist ein Ergebnis des Dateifinders.
+// Positions sind UTF-16-Offsets in RelativePath (passend zu JavaScript-Strings).
+type FileMatch struct {
+	Path         string `json:"path"`
+	RelativePath string `json:"relativePath"`
+	FileName     string `json:"fileName"`
+	Score        int    `json:"score"`
+	Positions    []int  `json:"positions"`
+	Recent       bool   `json:"recent"`
+}
+
+// FileFinderResult ist das Ergebnis von FindFiles.
+type FileFinderResult struct {
+	Query      string      `json:"query"`
+	Matches    []FileMatch `json:"matches"`
+	TotalFiles int         `json:"totalFiles"`
+	Error      string      `json:"error"`
+}
+
+// fileListCacheEntry speichert die Dateiliste eines Projekts (relative Pfade).
+type fileListCacheEntry struct {
+	files   []string
+	created time.Time
+}
+
+var fileListCache = make(map[string]*fileListCacheEntry)
+var fileListCacheMu sync.Mutex
+
+// FindFiles sucht Dateien im Projekt, deren Pfad unscharf auf query passt,
+// und gibt die besten limit Treffer zurück. Bei leerer Eingabe werden die
+// zuletzt geöffneten Dateien des Projekts geliefert.
+func (a *App) FindFiles(projectRoot, query string, limit int) FileFinderResult {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return FileFinderResult{Error: "Ungültiger Pfad: " + err.Error()}
+	}
+	if limit <= 0 {
+		limit = defaultFileFinderLimit
+	}
+
+	files := projectFileList(root)
+	result := FileFinderResult{
+		Query:      query,
+		Matches:    []FileMatch{},
+		TotalFiles: len(files),
+	}
+
+	recent := a.recentFileRanks(root)
+
+	pattern := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	if len(pattern) == 0 {
+		result.Matches = recentFileMatches(root, recent, limit)
+		return result
+	}
+
+	ranked := rankFiles(files, pattern, recent)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	// Positionen nur für die angezeigten Treffer berechnen
+	for _, r := range ranked {
+		rel := files[r.index]
+		_, positions := new(fuzzyScorer).score(rel, pattern, true)
+		result.Matches = append(result.Matches, FileMatch{
+			Path:         filepath.Join(root, filepath.FromSlash(rel)),
+			RelativePath: rel,
+			FileName:     filepath.Base(rel),
+			Score:        r.score,
+			Positions:    utf16Positions(rel, positions),
+			Recent:       recent[rel] > 0,
+		})
+	}
+	return result
+}
+
+// rankedFile ist ein bewerteter Index in die Dateiliste.
+type rankedFile struct {
+	index int
+	score int
+}
+
+// rankFiles bewertet alle Dateien parallel und sortiert nach Punkten.
+func rankFiles(files []string, pattern []rune, recent map[string]int) []rankedFile {
+	workers := runtime.NumCPU()
+	chunk := (len(files) + workers - 1) / workers
+	if chunk == 0 {
+		return nil
+	}
+
+	parts := make([][]rankedFile, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunk
+		if start >= len(files) {
+			break
+		}
+		end := min(start+chunk, len(files))
+
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			var scorer fuzzyScorer
+			for i := start; i < end; i++ {
+				rel := files[i]
+				if !isFuzzySubsequence(rel, pattern) {
+					continue
+				}
+				score, _ := scorer.score(rel, pattern, false)
+				score += recent[rel]
+				parts[w] = append(parts[w], rankedFile{index: i, score: score})
+			}
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	var ranked []rankedFile
+	for _, p := range parts {
+		ranked = append(ranked, p...)
+	}
+
+	// Bei gleicher Punktzahl: kürzerer Pfad, dann alphabetisch
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		pi, pj := files[ranked[i].index], files[ranked[j].index]
+		if len(pi) != len(pj) {
+			return len(pi) < len(pj)
+		}
+		return pi < pj
+	})
+	return ranked
+}
+
+// isFuzzySubsequence prüft schnell, ob alle Zeichen von pattern in dieser
+// Reihenfolge in path vorkommen (ohne Groß-/Kleinschreibung).
+func isFuzzySubsequence(path string, pattern []rune) bool {
+	i := 0
+	for _, r := range path {
+		if i < len(pattern) && unicode.ToLower(r) == pattern[i] {
+			i++
+		}
+	}
+	return i == len(pattern)
+}
+
+// fuzzyScorer hält wiederverwendbare Puffer, damit beim Bewerten vieler
+// Pfade nicht für jeden Pfad neuer Speicher angefordert werden muss.
+type fuzzyScorer struct {
+	path  []rune
+	bonus []int
+	prev  []int
+	cur   []int
+}
+
+// score berechnet die beste Bewertung für pattern in rel.
+// Dynamische Programmierung über (Musterzeichen × Pfadposition): Für jedes
+// Musterzeichen wird die beste Position gewählt, sodass Boni für Segment-,
+// Wort- und camelCase-Anfänge sowie zusammenhängende Treffer maximal und
+// Lücken minimal sind. Mit withPositions werden die gewählten Positionen
+// (Rune-Indizes) zurückgegeben.
+func (fs *fuzzyScorer) score(rel string, pattern []rune, withPositions bool) (int, []int) {
+	const negInf = -1 << 30
+	fs.path = fs.path[:0]
+	for _, r := range rel {
+		fs.path = append(fs.path, r)
+	}
+	path := fs.path
+	n, m := len(path), len(pattern)
+	if m == 0 || m > n {
+		return negInf, nil
+	}
+
+	baseStart := 0
+	for i := n - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			baseStart = i + 1
+			break
+		}
+	}
+
+	// Bonus je Pfadposition vorberechnen
+	bonus := growInts(&fs.bonus, n)
+	for j := 0; j < n; j++ {
+		b := 0
+		switch {
+		case j == 0 || path[j-1] == '/':
+			b = scoreSegmentStart
+		case path[j-1] == '_' || path[j-1] == '-' || path[j-1] == '.' || path[j-1] == ' ':
+			b = scoreWordStart
+		case unicode.IsUpper(path[j]) && unicode.IsLower(path[j-1]):
+			b = scoreCamelCase
+		}
+		if j >= baseStart {
+			b += scoreBasename
+		}
+		bonus[j] = b
+	}
+
+	prev := growInts(&fs.prev, n)
+	cur := growInts(&fs.cur, n)
+	var from [][]int
+	if withPositions {
+		from = make([][]int, m)
+	}
+
+	for i := 0; i < m; i++ {
+		if withPositions {
+			from[i] = make([]int, n)
+		}
+		// gapBest: beste Punktzahl eines Vorgängers j' < j-1 inkl. Lückenabzug
+		gapBest, gapFrom := negInf, -1
+		for j := 0; j < n; j++ {
+			cur[j] = negInf
+			if i > 0 {
+				if gapBest > negInf {
+					gapBest -= scoreGapPenalty
+				}
+				if j >= 2 && prev[j-2] > negInf && prev[j-2]-scoreGapPenalty >= gapBest {
+					gapBest, gapFrom = prev[j-2]-scoreGapPenalty, j-2
+				}
+			}
+
+			if unicode.ToLower(path[j]) != pattern[i] {
+				continue
+			}
+			s := scoreMatch + bonus[j]
+
+			if i == 0 {
+				cur[j] = s
+				continue
+			}
+
+			best, bestFrom := negInf, -1
+			if j >= 1 && prev[j-1] > negInf {
+				best, bestFrom = prev[j-1]+scoreConsecutive, j-1
+			}
+			if gapBest > best {
+				best, bestFrom = gapBest, gapFrom
+			}
+			if best == negInf {
+				continue
+			}
+			cur[j] = s + best
+			if withPositions {
+				from[i][j] = bestFrom
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	bestScore, bestEnd := negInf, -1
+	for j := 0; j < n; j++ {
+		if prev[j] > bestScore {
+			bestScore, bestEnd = prev[j], j
+		}
+	}
+	if bestEnd < 0 || !withPositions {
+		return bestScore, nil
+	}
+
+	positions := make([]int, m)
+	j := bestEnd
+	for i := m - 1; i >= 0; i-- {
+		positions[i] = j
+		if i > 0 {
+			j = from[i][j]
+		}
+	}
+	return bestScore, positions
+}
+
+// growInts passt einen Puffer auf Länge n an und gibt ihn zurück.
+func growInts(buf *[]int, n int) []int {
+	if cap(*buf) < n {
+		*buf = make([]int, n)
+	}
+	*buf = (*buf)[:n]
+	return *buf
+}
+
+// utf16Positions rechnet Rune-Indizes in UTF-16-Offsets um.
+func utf16Positions(s string, runePositions []int) []int {
+	if len(runePositions) == 0 {
+		return []int{}
+	}
+	offsets := make([]int, 0, len(runePositions))
+	offset, next := 0, 0
+	for i, r := range []rune(s) {
+		if next < len(runePositions) && runePositions[next] == i {
+			offsets = append(offsets, offset)
+			next++
+		}
+		offset += len(utf16.Encode([]rune{r}))
+	}
+	return offsets
+}
+
+// recentFileRanks bewertet die zuletzt geöffneten Dateien innerhalb des
+// Projekts (relativer Pfad -> Bonus, neueste Datei zuerst).
+func (a *App) recentFileRanks(root string) map[string]int {
+	ranks := make(map[string]int)
+	for i, path := range a.Config.RecentFiles {
+		bonus := scoreRecentMax - i*scoreRecentDecrease
+		if bonus <= 0 {
+			break
+		}
+		if !isPathWithinRoot(path, root) {
+			continue
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if _, exists := ranks[rel]; !exists {
+			ranks[rel] = bonus
+		}
+	}
+	return ranks
+}
+
+// recentFileMatches liefert die zuletzt geöffneten, noch existierenden Dateien.
+func recentFileMatches(root string, recent map[string]int, limit int) []FileMatch {
+	matches := []FileMatch{}
+	for rel, bonus := range recent {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		matches = append(matches, FileMatch{
+			Path:         path,
+			RelativePath: rel,
+			FileName:     filepath.Base(rel),
+			Score:        bonus,
+			Positions:    []int{},
+			Recent:       true,
+		})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// projectFileList gibt alle Dateien eines Projekts als relative Pfade
+// (mit "/") zurück. Die Liste wird für fileListCacheTTL zwischengespeichert.
+// Es gelten dieselben Ausschlüsse wie bei der Suche (.gitignore usw.),
+// aber ohne Größengrenze und Binär-Erkennung.
+func projectFileList(root string) []string {
+	fileListCacheMu.Lock()
+	entry, ok := fileListCache[root]
+	fileListCacheMu.Unlock()
+	if ok && time.Since(entry.created) < fileListCacheTTL {
+		return entry.files
+	}
+
+	filter := newProjectPathFilter(root, defaultSearchExcludes)
+	files := []string{}
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		if len(files) >= maxFileFinderFiles {
+			return filepath.SkipAll
+		}
+
+		// Versteckte Dateien und Ordner überspringen
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, ok := filter.relPath(path)
+		if ok && filter.excludesEntry(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !filter.isIncluded(path) {
+			return nil
+		}
+
+		if rel, err := filepath.Rel(root, path); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+
+	fileListCacheMu.Lock()
+	fileListCache[root] = &fileListCacheEntry{files: files, created: time.Now()}
+	fileListCacheMu.Unlock()
+
+	return files
+}
@@ -0,0 +1,267 @@
+// fileJournal.go — Rückgängig machen von Dateioperationen im Explorer.
+// Umbenennen, Löschen, Verschieben, Anlegen und Kopieren werden in einem
+// Journal festgehalten, zusammen mit allem, was zum Umkehren nötig ist.
+// Gelöschte Einträge liegen dabei im Papierkorb (siehe trash.go), der als
+// Ablage dient; rückgängig gemachtes Anlegen verschiebt dorthin.
+//
+// Einträge verfallen nach fileJournalMaxAge bzw. wenn mehr als
+// fileJournalMaxEntries vorhanden sind. Gelöschte Dateien bleiben dann im
+// Papierkorb und können weiterhin über RestoreFromTrash geholt werden.
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.UndoFileOperation() → rückgängig gemachte Operation
+//   window.go.main.App.RedoFileOperation() → wiederholte Operation
+//   window.go.main.App.GetFileJournal()    → FileJournalState
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	fileJournalMaxEntries = 100
+	fileJournalMaxAge     = 24 * time.Hour
+)
+
+// Werte für FileOperation.Kind
+const (
+	FileOpRename = "rename"
+	FileOpMove   = "move"
+	FileOpDelete = "delete"
+	FileOpCreate = "create"
+	FileOpCopy   = "copy"
+)
+
+// Werte für fileOpStep.kind
+const (
+	stepRename = "rename" // from → to umbenannt
+	stepTrash  = "trash"  // path liegt im Papierkorb (trashID)
+	stepCreate = "create" // path wurde neu angelegt
+)
+
+// FileOperation beschreibt einen Journal-Eintrag für das Frontend.
+// Paths enthält die betroffenen Pfade (z.B. alter und neuer Name), damit
+// das Frontend Explorer und offene Tabs aktualisieren kann.
+type FileOperation struct {
+	ID          string   `json:"id"`
+	Kind        string   `json:"kind"`
+	Description string   `json:"description"`
+	Paths       []string `json:"paths"`
+	Time        string   `json:"time"` // RFC3339
+}
+
+// FileJournalState listet die Einträge, die rückgängig gemacht bzw.
+// wiederholt werden können, jeweils den nächsten zuerst.
+type FileJournalState struct {
+	Undo []FileOperation `json:"undo"`
+	Redo []FileOperation `json:"redo"`
+}
+
+// fileOpStep ist ein einzelner umkehrbarer Schritt einer Operation.
+type fileOpStep struct {
+	kind    string
+	from    string
+	to      string
+	path    string
+	trashID string // Papierkorb-Eintrag, solange path gelöscht ist
+}
+
+// fileJournalEntry ist eine Operation aus einem oder mehreren Schritten.
+type fileJournalEntry struct {
+	op      FileOperation
+	created time.Time
+	steps   []*fileOpStep
+}
+
+// fileJournal speichert die umkehrbaren Operationen der laufenden Sitzung
+var fileJournal struct {
+	mu   sync.Mutex
+	undo []*fileJournalEntry
+	redo []*fileJournalEntry
+}
+var fileJournalCounter atomic.Uint64
+
+// UndoFileOperation macht die letzte Dateioperation rückgängig.
+// Schlägt ein Schritt fehl, werden die bereits umgekehrten Schritte wieder
+// hergestellt und die Operation bleibt im Journal.
+func (a *App) UndoFileOperation() (FileOperation, error) {
+	fileJournal.mu.Lock()
+	defer fileJournal.mu.Unlock()
+
+	pruneFileJournal()
+	if len(fileJournal.undo) == 0 {
+		return FileOperation{}, fmt.Errorf("Nichts zum Rückgängigmachen")
+	}
+	entry := fileJournal.undo[len(fileJournal.undo)-1]
+
+	for i := len(entry.steps) - 1; i >= 0; i-- {
+		if err := entry.steps[i].undo(); err != nil {
+			for _, step := range entry.steps[i+1:] {
+				step.redo()
+			}
+			return entry.op, fmt.Errorf("Rückgängig fehlgeschlagen: %w", err)
+		}
+	}
+
+	fileJournal.undo = fileJournal.undo[:len(fileJournal.undo)-1]
+	fileJournal.redo = append(fileJournal.redo, entry)
+	return entry.op, nil
+}
+
+// RedoFileOperation wiederholt die zuletzt rückgängig gemachte Operation.
+func (a *App) RedoFileOperation() (FileOperation, error) {
+	fileJournal.mu.Lock()
+	defer fileJournal.mu.Unlock()
+
+	pruneFileJournal()
+	if len(fileJournal.redo) == 0 {
+		return FileOperation{}, fmt.Errorf("Nichts zum Wiederholen")
+	}
+	entry := fileJournal.redo[len(fileJournal.redo)-1]
+
+	for i, step := range entry.steps {
+		if err := step.redo(); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				entry.steps[j].undo()
+			}
+			return entry.op, fmt.Errorf("Wiederholen fehlgeschlagen: %w", err)
+		}
+	}
+
+	fileJournal.redo = fileJournal.redo[:len(fileJournal.redo)-1]
+	fileJournal.undo = append(fileJournal.undo, entry)
+	return entry.op, nil
+}
+
+// GetFileJournal liefert die Einträge für Menüs wie "Rückgängig: Umbenennen".
+func (a *App) GetFileJournal() FileJournalState {
+	fileJournal.mu.Lock()
+	defer fileJournal.mu.Unlock()
+
+	pruneFileJournal()
+	state := FileJournalState{Undo: []FileOperation{}, Redo: []FileOperation{}}
+	for i := len(fileJournal.undo) - 1; i >= 0; i-- {
+		state.Undo = append(state.Undo, fileJournal.undo[i].op)
+	}
+	for i := len(fileJournal.redo) - 1; i >= 0; i-- {
+		state.Redo = append(state.Redo, fileJournal.redo[i].op)
+	}
+	return state
+}
+
+// recordFileOperation trägt eine ausgeführte Operation ins Journal ein.
+// Eine neue Operation verwirft die Wiederholen-Liste.
+func recordFileOperation(kind, description string, paths []string, steps ...*fileOpStep) {
+	if len(steps) == 0 {
+		return
+	}
+
+	now := time.Now()
+	entry := &fileJournalEntry{
+		op: FileOperation{
+			ID:          fmt.Sprintf("fileop-%d", fileJournalCounter.Add(1)),
+			Kind:        kind,
+			Description: description,
+			Paths:       paths,
+			Time:        now.Format(time.RFC3339),
+		},
+		created: now,
+		steps:   steps,
+	}
+
+	fileJournal.mu.Lock()
+	defer fileJournal.mu.Unlock()
+
+	fileJournal.undo = append(fileJournal.undo, entry)
+	fileJournal.redo = nil
+	pruneFileJournal()
+}
+
+// pruneFileJournal entfernt abgelaufene Einträge. Muss mit gesperrtem
+// fileJournal.mu aufgerufen werden.
+func pruneFileJournal() {
+	cutoff := time.Now().Add(-fileJournalMaxAge)
+	prune := func(entries []*fileJournalEntry) []*fileJournalEntry {
+		start := max(len(entries)-fileJournalMaxEntries, 0)
+		for start < len(entries) && entries[start].created.Before(cutoff) {
+			start++
+		}
+		return entries[start:]
+	}
+	fileJournal.undo = prune(fileJournal.undo)
+	fileJournal.redo = prune(fileJournal.redo)
+}
+
+// renameStep, trashStep und createStep erzeugen die Schritte für
+// recordFileOperation.
+func renameStep(from, to string) *fileOpStep {
+	return &fileOpStep{kind: stepRename, from: from, to: to}
+}
+
+func trashStep(path, trashID string) *fileOpStep {
+	return &fileOpStep{kind: stepTrash, path: path, trashID: trashID}
+}
+
+func createStep(path string) *fileOpStep {
+	return &fileOpStep{kind: stepCreate, path: path}
+}
+
+// undo kehrt den Schritt um.
+func (s *fileOpStep) undo() error {
+	switch s.kind {
+	case stepRename:
+		return moveIfFree(s.to, s.from)
+	case stepTrash:
+		if _, err := restoreFromTrash(s.trashID, s.path); err != nil {
+			return err
+		}
+		s.trashID = ""
+		return nil
+	case stepCreate:
+		id, err := moveToTrash(s.path)
+		if err != nil {
+			return err
+		}
+		s.trashID = id
+		return nil
+	}
+	return fmt.Errorf("Unbekannter Schritt: %s", s.kind)
+}
+
+// redo führt den Schritt erneut aus.
+func (s *fileOpStep) redo() error {
+	switch s.kind {
+	case stepRename:
+		return moveIfFree(s.from, s.to)
+	case stepTrash:
+		id, err := moveToTrash(s.path)
+		if err != nil {
+			return err
+		}
+		s.trashID = id
+		return nil
+	case stepCreate:
+		if _, err := restoreFromTrash(s.trashID, s.path); err != nil {
+			return err
+		}
+		s.trashID = ""
+		return nil
+	}
+	return fmt.Errorf("Unbekannter Schritt: %s", s.kind)
+}
+
+// moveIfFree verschiebt src nach dst, ohne ein vorhandenes Ziel zu ersetzen.
+func moveIfFree(src, dst string) error {
+	if _, err := os.Lstat(src); err != nil {
+		return fmt.Errorf("Datei existiert nicht mehr: %s", src)
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("Ziel existiert bereits: %s", filepath.Base(dst))
+	}
+	return moveAcrossDevices(src, dst)
+}
@@ -1,10 +1,15 @@
 // fileOperations.go — Alle Dateioperationen (Lesen, Speichern, Dialoge).
 // Diese Funktionen werden vom Frontend über Wails-Bindings aufgerufen:
-//   window.go.main.App.LoadFile()      → Öffnen-Dialog
-//   window.go.main.App.SaveFile()      → Direkt speichern
-//   window.go.main.App.SaveFileUnder() → Speichern-unter-Dialog
-//   window.go.main.App.ReadTextFile()  → Textdatei ohne Dialog lesen
-//   window.go.main.App.ReadBinaryFile()→ Binärdatei als Base64 lesen (Bilder, PDFs)
+//   window.go.main.App.LoadFile()           → Öffnen-Dialog
+//   window.go.main.App.SaveFile()           → Direkt speichern
+//   window.go.main.App.SaveFileChecked()    → Speichern mit Konfliktprüfung (fileConflict.go)
+//   window.go.main.App.SaveFileWithFormat() → Wie SaveFileChecked, Kodierung wählbar
+//   window.go.main.App.SaveFileUnder()      → Speichern-unter-Dialog
+//   window.go.main.App.ReadTextFile()       → Textdatei ohne Dialog lesen
+//   window.go.main.App.ReadBinaryFile()     → Binärdatei als Base64 lesen (Bilder, PDFs)
+//
+// Kodierung, BOM und Zeilenenden werden beim Lesen erkannt und beim Speichern
+// beibehalten (siehe textEncoding.go).
 //
 // Alle Ergebnisse werden als JSON-Structs zurückgegeben, die Wails
 // automatisch in JavaScript-Objekte konvertiert.
@@ -30,29 +35,44 @@ type BinaryFileResult struct {
 	MimeType string `json:"mimeType"` // Erkannter MIME-Typ (z.B. "image/png")
 	IsImage  bool   `json:"isImage"`  // true wenn Bilddatei
 	IsPdf    bool   `json:"isPdf"`    // true wenn PDF
+	TooLarge bool   `json:"tooLarge"` // Zu groß, stattdessen ReadFileChunk verwenden
 	Error    string `json:"error"`    // Fehlermeldung (leer bei Erfolg)
 }
 
 // SaveResult: Ergebnis einer Speicher-Operation.
+// Fingerprint beschreibt die gespeicherte Datei, Conflict ist nur bei
+// SaveFileChecked gesetzt, wenn die Datei zwischenzeitlich geändert wurde.
 type SaveResult struct {
-	Success bool   `json:"success"`
-	Path    string `json:"path"`            // Vollständiger Dateipfad
-	Title   string `json:"title"`           // Nur der Dateiname (für Tab-Titel)
-	Message string `json:"message,omitempty"`
+	Success     bool            `json:"success"`
+	Path        string          `json:"path"`  // Vollständiger Dateipfad
+	Title       string          `json:"title"` // Nur der Dateiname (für Tab-Titel)
+	Message     string          `json:"message,omitempty"`
+	Fingerprint FileFingerprint `json:"fingerprint"`
+	Conflict    *SaveConflict   `json:"conflict,omitempty"`
 }
 
 // SaveRequest: Eingabedaten für "Speichern unter" (kommt als JSON vom Frontend).
+// Format ist optional; leere Felder übernehmen das Format einer bestehenden
+// Zieldatei bzw. UTF-8 mit LF.
 type SaveRequest struct {
-	Content     string `json:"content"`
-	DefaultPath string `json:"defaultPath"`
+	Content     string     `json:"content"`
+	DefaultPath string     `json:"defaultPath"`
+	Format      TextFormat `json:"format"`
 }
 
 // FileResult: Ergebnis beim Lesen einer Textdatei.
 // JSON-Tags sorgen dafür, dass Wails die Felder korrekt ans Frontend übergibt.
+// Fingerprint wird beim Speichern an SaveFileChecked zurückgegeben.
+// Content ist immer UTF-8 (ohne BOM); Format beschreibt die Datei auf der Festplatte.
+// TooLarge ist gesetzt, wenn die Datei im Großdatei-Modus geöffnet werden
+// sollte (siehe largeFile.go); Content ist dann leer.
 type FileResult struct {
-	Content  string `json:"content"`
-	Filename string `json:"filename"`
-	Error    string `json:"error"`
+	Content     string          `json:"content"`
+	Filename    string          `json:"filename"`
+	Format      TextFormat      `json:"format"`
+	Fingerprint FileFingerprint `json:"fingerprint"`
+	TooLarge    bool            `json:"tooLarge"`
+	Error       string          `json:"error"`
 }
 
 func (a *App) SaveFileUnder(jsonInput string) SaveResult {
@@ -79,27 +99,63 @@ func (a *App) SaveFileUnder(jsonInput string) SaveResult {
 		fileMode = info.Mode()
 	}
 
-	if err := os.WriteFile(filename, []byte(req.Content), fileMode); err != nil {
+	data, err := encodeForSave(filename, req.Content, req.Format)
+	if err != nil {
 		return SaveResult{Success: false, Message: err.Error()}
 	}
 
-	return SaveResult{
+	recordFileHistory(filename)
+	if err := os.WriteFile(filename, data, fileMode); err != nil {
+		return SaveResult{Success: false, Message: err.Error()}
+	}
+	markSearchIndexDirty(filename)
+
+	result := SaveResult{
 		Success: true,
 		Path:    filename,
 		Title:   filepath.Base(filename), // Extrahiert den Dateinamen für den Tab-Titel
 	}
+	if info, err := os.Stat(filename); err == nil {
+		result.Fingerprint = newFingerprint(info, data)
+	}
+	return result
 }
 
 // ReadTextFile liest eine Textdatei direkt über den Pfad (ohne Dialog)
 func (a *App) ReadTextFile(path string) FileResult {
-	data, err := os.ReadFile(path)
+	resolved, err := guardProjectPath(path, true)
+	if err != nil {
+		return FileResult{Filename: path, Error: err.Error()}
+	}
+	path = resolved
+	if result, tooLarge := checkFileSize(path); tooLarge {
+		return result
+	}
+
+	data, fingerprint, err := readFileWithFingerprint(path)
 	if err != nil {
 		return FileResult{Error: fmt.Sprintf("Fehler beim Lesen: %v", err)}
 	}
+	content, format := decodeTextFile(data)
 	return FileResult{
-		Content:  string(data),
-		Filename: path,
+		Content:     content,
+		Filename:    path,
+		Format:      format,
+		Fingerprint: fingerprint,
+	}
+}
+
+// checkFileSize prüft, ob eine Datei für das Laden am Stück zu groß ist.
+func checkFileSize(path string) (FileResult, bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() <= largeFileThreshold {
+		return FileResult{}, false
 	}
+	return FileResult{
+		Filename: path,
+		TooLarge: true,
+		Error:    fmt.Sprintf("Datei ist zu groß (%d MB), bitte im Großdatei-Modus öffnen", info.Size()/(1024*1024)),
+	}, true
 }
 
 // LoadFile öffnet einen Datei-Dialog
@@ -118,30 +174,35 @@ func (a *App) LoadFile() FileResult {
 	if filename == "" {
 		return FileResult{Error: "Fehler: Abgebrochen"}
 	}
+	if result, tooLarge := checkFileSize(filename); tooLarge {
+		return result
+	}
 
-	data, err := os.ReadFile(filename)
+	data, fingerprint, err := readFileWithFingerprint(filename)
 	if err != nil {
 		return FileResult{Error: fmt.Sprintf("Fehler beim Lesen: %v", err)}
 	}
 
+	content, format := decodeTextFile(data)
 	return FileResult{
-		Content:  string(data),
-		Filename: filename,
+		Content:     content,
+		Filename:    filename,
+		Format:      format,
+		Fingerprint: fingerprint,
 	}
 }
 
-// SaveFile speichert eine Datei – returns error for Wails auto-conversion
+// SaveFile speichert eine Datei – returns error for Wails auto-conversion.
+// Ohne Fingerabdruck gibt es keine Konfliktprüfung; Tabs mit bekanntem
+// Stand speichern über SaveFileChecked.
 func (a *App) SaveFile(content string, filename string) error {
-	if filename == "" {
-		return fmt.Errorf("Dateiname darf nicht leer sein")
-	}
-
-	dir := filepath.Dir(filename)
-	_, err := os.Stat(dir)
-	if os.IsNotExist(err) {
-		return fmt.Errorf("Ordner Schreiben fehlgeschlagen: %w", err)
-	}
+	_, err := saveFileChecked(filename, content, TextFormat{}, FileFingerprint{})
+	return err
+}
 
+// writeFileAtomic schreibt Daten atomar in eine Datei.
+// Bestehende Dateirechte bleiben erhalten, neue Dateien bekommen 0644.
+func writeFileAtomic(filename string, data []byte) error {
 	// Bestehende Dateirechte auslesen (falls Datei existiert).
 	// Standard: 0644 für neue Dateien.
 	fileMode := os.FileMode(0644)
@@ -153,7 +214,7 @@ func (a *App) SaveFile(content string, filename string) error {
 	// Das verhindert Datenverlust, falls der Schreibvorgang unterbrochen wird
 	// (z.B. Stromausfall) — die Originaldatei bleibt intakt.
 	tempFile := filename + ".tmp"
-	if err := os.WriteFile(tempFile, []byte(content), fileMode); err != nil {
+	if err := os.WriteFile(tempFile, data, fileMode); err != nil {
 		os.Remove(tempFile) // Cleanup failed temp file
 		return fmt.Errorf("Schreiben fehlgeschlagen: %w", err)
 	}
@@ -168,6 +229,17 @@ func (a *App) SaveFile(content string, filename string) error {
 
 // ReadBinaryFile liest eine Binärdatei und gibt Base64 zurück
 func (a *App) ReadBinaryFile(path string) BinaryFileResult {
+	path, err := guardProjectPath(path, true)
+	if err != nil {
+		return BinaryFileResult{Error: err.Error()}
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > largeFileThreshold {
+		return BinaryFileResult{
+			TooLarge: true,
+			Error:    fmt.Sprintf("Datei ist zu groß (%d MB)", info.Size()/(1024*1024)),
+		}
+	}
+
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return BinaryFileResult{Error: fmt.Sprintf("Fehler beim Lesen: %v", err)}
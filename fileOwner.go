@@ -0,0 +1,39 @@
+//go:build !windows
+
+// fileOwner.go - Besitzer einer Datei für FileEntry (siehe fileExplorer.go).
+package main
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+// fileOwnerNames speichert aufgelöste Benutzernamen (uid -> Name),
+// damit nicht für jede Datei /etc/passwd gelesen wird.
+var fileOwnerNames = make(map[uint32]string)
+var fileOwnerNamesMu sync.Mutex
+
+// fileOwner gibt den Benutzernamen des Besitzers zurück, ersatzweise die uid.
+func fileOwner(info os.FileInfo) string {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+
+	fileOwnerNamesMu.Lock()
+	defer fileOwnerNamesMu.Unlock()
+
+	if name, ok := fileOwnerNames[stat.Uid]; ok {
+		return name
+	}
+	uid := strconv.FormatUint(uint64(stat.Uid), 10)
+	name := uid
+	if u, err := user.LookupId(uid); err == nil {
+		name = u.Username
+	}
+	fileOwnerNames[stat.Uid] = name
+	return name
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+// fileOwner_windows.go - Stub für Windows (Besitzer wird nicht ermittelt).
+package main
+
+import "os"
+
+func fileOwner(info os.FileInfo) string {
+	return ""
+}
@@ -0,0 +1,720 @@
+// fileTransfer.go — Anlegen, Kopieren, Verschieben und Duplizieren im Explorer.
+// Kopieren und Verschieben laufen als abbrechbarer Hintergrund-Job, da
+// Ordner beliebig groß sein können. Der Fortschritt wird über Events
+// gemeldet:
+//   filetransfer_progress_<id> → FileTransferProgress (höchstens alle 100ms)
+//   filetransfer_done_<id>     → FileTransferSummary
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.CreateFile(path, projectRoot)
+//   window.go.main.App.CreateDirectory(path, projectRoot)
+//   window.go.main.App.DuplicateFile(path, projectRoot)   → Pfad der Kopie
+//   window.go.main.App.CopyFiles(request)                 → Transfer-ID
+//   window.go.main.App.MoveFiles(request)                 → Transfer-ID
+//   window.go.main.App.CancelFileTransfer(transferId)
+//
+// Ist projectRoot gesetzt, müssen alle Quellen und Ziele innerhalb des
+// Projekts liegen (siehe resolveProjectPath). Leer = Datei-Explorer ohne Projekt.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Werte für FileTransferRequest.Collision
+const (
+	CollisionSkip      = "skip"      // Vorhandenes Ziel behalten
+	CollisionOverwrite = "overwrite" // Ersetzen (Altes in den Papierkorb); Ordner werden zusammengeführt
+	CollisionRename    = "rename"    // "foo.txt" → "foo (2).txt" (Standard)
+)
+
+const (
+	fileTransferProgressInterval = 100 * time.Millisecond
+	fileCopyBufferSize           = 1024 * 1024
+)
+
+// FileTransferRequest: Eingabedaten für CopyFiles und MoveFiles.
+// Jede Quelle landet unter ihrem Namen in TargetDir.
+type FileTransferRequest struct {
+	Sources     []string `json:"sources"`
+	TargetDir   string   `json:"targetDir"`
+	Collision   string   `json:"collision"`
+	ProjectRoot string   `json:"projectRoot"`
+}
+
+// FileTransferProgress wird als filetransfer_progress_<id> gesendet.
+type FileTransferProgress struct {
+	TransferID  string `json:"transferId"`
+	FilesDone   int    `json:"filesDone"`
+	FilesTotal  int    `json:"filesTotal"`
+	BytesDone   int64  `json:"bytesDone"`
+	BytesTotal  int64  `json:"bytesTotal"`
+	CurrentPath string `json:"currentPath"`
+}
+
+// FileTransferSummary wird als filetransfer_done_<id> gesendet.
+// Targets enthält die tatsächlichen Zielpfade (nach Umbenennung),
+// Skipped die Quellen, die wegen CollisionSkip übersprungen wurden.
+// Errors sammelt Fehler einzelner Dateien; Error ist ein Abbruchgrund.
+type FileTransferSummary struct {
+	TransferID string   `json:"transferId"`
+	Move       bool     `json:"move"`
+	Targets    []string `json:"targets"`
+	Skipped    []string `json:"skipped"`
+	Errors     []string `json:"errors"`
+	Cancelled  bool     `json:"cancelled"`
+	DurationMs int64    `json:"durationMs"`
+	Error      string   `json:"error"`
+}
+
+// fileTransferJob repräsentiert einen laufenden Kopier- oder Verschiebevorgang.
+type fileTransferJob struct {
+	ID     string
+	cancel context.CancelFunc
+}
+
+// fileTransferItem ist eine Quelle mit ihrem gewünschten Ziel.
+type fileTransferItem struct {
+	src string
+	dst string
+}
+
+// fileTransferJobs speichert alle laufenden Transfers (transferId -> job)
+var fileTransferJobs = make(map[string]*fileTransferJob)
+var fileTransferJobsMu sync.Mutex
+var fileTransferCounter atomic.Uint64
+
+// fileCopier kopiert Dateien und meldet den Fortschritt. Ein nil-Copier
+// (z.B. bei DuplicateFile) kopiert ohne Events.
+// steps sammelt die Journal-Schritte des aktuellen Eintrags (siehe
+// transferEntry, replaceEntry und mergeTree).
+type fileCopier struct {
+	app      *App
+	ctx      context.Context
+	progress FileTransferProgress
+	lastEmit time.Time
+	steps    []*fileOpStep
+}
+
+// Ergebnis von resolveCollision
+const (
+	collisionFree    = iota // Ziel ist frei
+	collisionSkip           // Eintrag überspringen
+	collisionMerge          // Vorhandenen Ordner zusammenführen
+	collisionReplace        // Vorhandenes Ziel ersetzen
+)
+
+// CreateFile legt eine leere Datei an. Existiert sie bereits, gibt es einen Fehler.
+func (a *App) CreateFile(path, projectRoot string) error {
+	path, err := checkExplorerPath(path, projectRoot, false)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("Ordner existiert nicht: %s", filepath.Dir(path))
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return fmt.Errorf("Ziel existiert bereits: %s", filepath.Base(path))
+	}
+	if err != nil {
+		return fmt.Errorf("Anlegen fehlgeschlagen: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	recordFileOperation(FileOpCreate, "Neue Datei: "+filepath.Base(path), []string{path}, createStep(path))
+	return nil
+}
+
+// CreateDirectory legt einen Ordner an (auch verschachtelt, z.B. "a/b/c").
+func (a *App) CreateDirectory(path, projectRoot string) error {
+	path, err := checkExplorerPath(path, projectRoot, false)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("Ziel existiert bereits: %s", filepath.Base(path))
+	}
+
+	// Obersten neu angelegten Ordner merken, damit Rückgängig alles entfernt
+	created := path
+	for parent := filepath.Dir(created); parent != created; parent = filepath.Dir(created) {
+		if _, err := os.Lstat(parent); err == nil {
+			break
+		}
+		created = parent
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("Anlegen fehlgeschlagen: %w", err)
+	}
+	recordFileOperation(FileOpCreate, "Neuer Ordner: "+filepath.Base(path), []string{path}, createStep(created))
+	return nil
+}
+
+// DuplicateFile kopiert eine Datei oder einen Ordner neben das Original
+// ("foo.txt" → "foo (2).txt") und gibt den neuen Pfad zurück.
+func (a *App) DuplicateFile(path, projectRoot string) (string, error) {
+	path, err := checkExplorerPath(path, projectRoot, false)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return "", fmt.Errorf("Quelle existiert nicht: %s", path)
+	}
+
+	target := uniqueFileName(path)
+	if err := (*fileCopier)(nil).copyTree(path, target); err != nil {
+		os.RemoveAll(target)
+		return "", fmt.Errorf("Duplizieren fehlgeschlagen: %w", err)
+	}
+	recordFileOperation(FileOpCopy, "Duplizieren: "+filepath.Base(path), []string{path, target}, createStep(target))
+	return target, nil
+}
+
+// CopyFiles kopiert Dateien und Ordner im Hintergrund nach req.TargetDir.
+func (a *App) CopyFiles(req FileTransferRequest) (string, error) {
+	return a.startFileTransfer(req, false)
+}
+
+// MoveFiles verschiebt Dateien und Ordner im Hintergrund nach req.TargetDir.
+// Auf demselben Dateisystem wird nur umbenannt, sonst kopiert und gelöscht.
+func (a *App) MoveFiles(req FileTransferRequest) (string, error) {
+	return a.startFileTransfer(req, true)
+}
+
+// CancelFileTransfer bricht einen laufenden Transfer ab. Bereits kopierte
+// Dateien bleiben erhalten, die gerade kopierte Datei wird verworfen.
+func (a *App) CancelFileTransfer(transferId string) error {
+	fileTransferJobsMu.Lock()
+	job, exists := fileTransferJobs[transferId]
+	fileTransferJobsMu.Unlock()
+
+	if exists {
+		job.cancel()
+	}
+	return nil
+}
+
+// cancelAllFileTransfers bricht alle laufenden Transfers ab (beim Beenden der App).
+func cancelAllFileTransfers() {
+	fileTransferJobsMu.Lock()
+	defer fileTransferJobsMu.Unlock()
+
+	for _, job := range fileTransferJobs {
+		job.cancel()
+	}
+}
+
+// startFileTransfer prüft die Anfrage und startet den Job.
+// Ungültige Eingaben werden sofort als Fehler gemeldet.
+func (a *App) startFileTransfer(req FileTransferRequest, move bool) (string, error) {
+	switch req.Collision {
+	case "":
+		req.Collision = CollisionRename
+	case CollisionSkip, CollisionOverwrite, CollisionRename:
+	default:
+		return "", fmt.Errorf("Unbekannte Kollisionsregel: %s", req.Collision)
+	}
+	if len(req.Sources) == 0 {
+		return "", fmt.Errorf("Keine Dateien ausgewählt")
+	}
+
+	targetDir, err := checkExplorerPath(req.TargetDir, req.ProjectRoot, true)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(targetDir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("Zielordner existiert nicht: %s", targetDir)
+	}
+
+	items := make([]fileTransferItem, 0, len(req.Sources))
+	for _, source := range req.Sources {
+		src, err := checkExplorerPath(source, req.ProjectRoot, false)
+		if err != nil {
+			return "", err
+		}
+		info, err := os.Lstat(src)
+		if err != nil {
+			return "", fmt.Errorf("Quelle existiert nicht: %s", src)
+		}
+		if info.IsDir() && isPathWithinRoot(targetDir, src) {
+			return "", fmt.Errorf("Ordner kann nicht in sich selbst kopiert werden: %s", filepath.Base(src))
+		}
+		if filepath.Dir(src) == src {
+			return "", fmt.Errorf("Dieses Verzeichnis darf nicht verschoben werden")
+		}
+		items = append(items, fileTransferItem{src: src, dst: filepath.Join(targetDir, filepath.Base(src))})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &fileTransferJob{
+		ID:     fmt.Sprintf("transfer-%d", fileTransferCounter.Add(1)),
+		cancel: cancel,
+	}
+
+	fileTransferJobsMu.Lock()
+	fileTransferJobs[job.ID] = job
+	fileTransferJobsMu.Unlock()
+
+	go a.runFileTransfer(ctx, job, items, req.Collision, move)
+
+	return job.ID, nil
+}
+
+// runFileTransfer kopiert bzw. verschiebt alle Einträge nacheinander.
+func (a *App) runFileTransfer(ctx context.Context, job *fileTransferJob, items []fileTransferItem, collision string, move bool) {
+	started := time.Now()
+	summary := FileTransferSummary{TransferID: job.ID, Move: move, Targets: []string{}, Skipped: []string{}, Errors: []string{}}
+
+	c := &fileCopier{app: a, ctx: ctx, progress: FileTransferProgress{TransferID: job.ID}}
+	for _, item := range items {
+		files, bytes := measureTree(item.src)
+		c.progress.FilesTotal += files
+		c.progress.BytesTotal += bytes
+	}
+	c.emit()
+
+	// Ersetzte Ziele liegen im Papierkorb, daher ist alles umkehrbar
+	var steps []*fileOpStep
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+
+		dst, action, err := resolveCollision(item.src, item.dst, collision, move)
+		if err == nil && action == collisionSkip {
+			summary.Skipped = append(summary.Skipped, item.src)
+			files, bytes := measureTree(item.src)
+			c.advance(files, bytes, item.src)
+			continue
+		}
+		c.steps = nil
+		if err == nil {
+			switch action {
+			case collisionMerge:
+				err = c.mergeTree(item.src, dst, move)
+			case collisionReplace:
+				err = c.replaceEntry(item.src, dst, move)
+			default:
+				err = c.transferEntry(item.src, dst, move)
+			}
+		}
+		// Auch nach Fehler oder Abbruch: Erledigtes bleibt umkehrbar
+		steps = append(steps, c.steps...)
+
+		switch {
+		case ctx.Err() != nil:
+		case err != nil:
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", filepath.Base(item.src), err))
+		default:
+			summary.Targets = append(summary.Targets, dst)
+		}
+	}
+
+	if len(steps) > 0 {
+		kind, verb := FileOpCopy, "Kopieren"
+		if move {
+			kind, verb = FileOpMove, "Verschieben"
+		}
+		description := fmt.Sprintf("%s: %d Einträge", verb, len(items))
+		if len(summary.Targets) == 1 {
+			description = fmt.Sprintf("%s: %s", verb, filepath.Base(summary.Targets[0]))
+		}
+		recordFileOperation(kind, description, summary.Targets, steps...)
+	}
+
+	summary.Cancelled = ctx.Err() != nil
+	summary.DurationMs = time.Since(started).Milliseconds()
+	c.emit()
+	if a.ctx != nil {
+		wailsRuntime.EventsEmit(a.ctx, fmt.Sprintf("filetransfer_done_%s", job.ID), summary)
+	}
+
+	// Job bereinigen
+	fileTransferJobsMu.Lock()
+	delete(fileTransferJobs, job.ID)
+	fileTransferJobsMu.Unlock()
+	job.cancel()
+}
+
+// resolveCollision bestimmt das tatsächliche Ziel für src und wie mit
+// einem vorhandenen Ziel umzugehen ist (collisionFree, collisionSkip, ...).
+func resolveCollision(src, dst, collision string, move bool) (string, int, error) {
+	dstInfo, err := os.Lstat(dst)
+	if os.IsNotExist(err) {
+		return dst, collisionFree, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Quelle liegt bereits im Zielordner
+	if src == dst {
+		if collision == CollisionRename && !move {
+			return uniqueFileName(dst), collisionFree, nil
+		}
+		return "", collisionSkip, nil
+	}
+
+	switch collision {
+	case CollisionSkip:
+		return "", collisionSkip, nil
+	case CollisionRename:
+		return uniqueFileName(dst), collisionFree, nil
+	}
+
+	// Überschreiben: Ordner werden zusammengeführt, sonst wird das Ziel ersetzt
+	srcInfo, err := os.Lstat(src)
+	if err != nil {
+		return "", 0, err
+	}
+	if srcInfo.IsDir() && dstInfo.IsDir() {
+		return dst, collisionMerge, nil
+	}
+	return dst, collisionReplace, nil
+}
+
+// transferEntry kopiert bzw. verschiebt src an das freie Ziel dst.
+func (c *fileCopier) transferEntry(src, dst string, move bool) error {
+	if move {
+		if err := c.moveTree(src, dst); err != nil {
+			return err
+		}
+		c.steps = append(c.steps, renameStep(src, dst))
+		return nil
+	}
+	if err := c.copyTree(src, dst); err != nil {
+		return err
+	}
+	c.steps = append(c.steps, createStep(dst))
+	return nil
+}
+
+// replaceEntry ersetzt das vorhandene Ziel dst durch src. Der Ersatz wird
+// zuerst unter einem temporären Namen fertiggestellt; erst dann wandert
+// dst in den Papierkorb. Schlägt das Kopieren fehl, bleibt dst unverändert.
+func (c *fileCopier) replaceEntry(src, dst string, move bool) error {
+	temp := replaceTempPath(dst)
+	if move {
+		if err := c.moveTree(src, temp); err != nil {
+			return err
+		}
+	} else if err := c.copyTree(src, temp); err != nil {
+		os.RemoveAll(temp) // Unvollständige eigene Kopie
+		return err
+	}
+
+	// Ersatz zurücknehmen, falls dst nicht ersetzt werden kann
+	undoTemp := func() {
+		if move {
+			os.Rename(temp, src)
+		} else {
+			os.RemoveAll(temp)
+		}
+	}
+
+	trashID, err := moveToTrash(dst)
+	if err != nil {
+		undoTemp()
+		return fmt.Errorf("Ziel konnte nicht ersetzt werden: %w", err)
+	}
+	if err := os.Rename(temp, dst); err != nil {
+		restoreFromTrash(trashID, dst)
+		undoTemp()
+		return fmt.Errorf("Ziel konnte nicht ersetzt werden: %w", err)
+	}
+
+	c.steps = append(c.steps, trashStep(dst, trashID))
+	if move {
+		c.steps = append(c.steps, renameStep(src, dst))
+	} else {
+		c.steps = append(c.steps, createStep(dst))
+	}
+	return nil
+}
+
+// mergeTree führt den Ordner src in den vorhandenen Ordner dst zusammen:
+// Neue Einträge werden übertragen, gleichnamige Ordner zusammengeführt und
+// andere vorhandene Einträge ersetzt (siehe replaceEntry). Beim Verschieben
+// wandert der geleerte Quellordner in den Papierkorb, damit er beim
+// Rückgängigmachen für die zurückverschobenen Einträge wieder bereitsteht.
+func (c *fileCopier) mergeTree(src, dst string, move bool) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := c.cancelled(); err != nil {
+			return err
+		}
+		from, to := filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())
+
+		existing, err := os.Lstat(to)
+		switch {
+		case err != nil:
+			err = c.transferEntry(from, to, move)
+		case existing.IsDir() && entry.IsDir():
+			err = c.mergeTree(from, to, move)
+		default:
+			err = c.replaceEntry(from, to, move)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if move {
+		trashID, err := moveToTrash(src)
+		if err != nil {
+			return fmt.Errorf("Quelle konnte nicht entfernt werden: %w", err)
+		}
+		c.steps = append(c.steps, trashStep(src, trashID))
+	}
+	return nil
+}
+
+// replaceTempPath liefert einen freien, versteckten Namen neben dst für
+// den Ersatz, solange dst noch existiert.
+func replaceTempPath(dst string) string {
+	temp := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp")
+	if _, err := os.Lstat(temp); err == nil {
+		return uniqueFileName(temp)
+	}
+	return temp
+}
+
+// duplicateSuffix erkennt eine bereits vorhandene Nummer: "foo (2)"
+var duplicateSuffix = regexp.MustCompile(`^(.*) \((\d+)\)$`)
+
+// uniqueFileName sucht einen freien Namen nach dem Muster "foo (2).txt".
+// Eine vorhandene Nummer wird weitergezählt ("foo (2).txt" → "foo (3).txt").
+func uniqueFileName(path string) string {
+	dir, name := filepath.Split(path)
+
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if info, err := os.Lstat(path); (err == nil && info.IsDir()) || stem == "" {
+		stem, ext = name, "" // Ordner und ".bashrc" haben keine Endung
+	}
+
+	n := 2
+	if m := duplicateSuffix.FindStringSubmatch(stem); m != nil {
+		stem = m[1]
+		n, _ = strconv.Atoi(m[2])
+		n++
+	}
+
+	for ; ; n++ {
+		candidate := filepath.Join(dir, fmt.Sprintf("%s (%d)%s", stem, n, ext))
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// checkExplorerPath normalisiert path und prüft, dass er innerhalb von
+// projectRoot liegt (falls gesetzt), auch nach Auflösen von Symlinks (siehe
+// resolveProjectPath). followLast bestimmt, ob der letzte Bestandteil
+// aufgelöst wird (Zielordner) oder nicht (kopierte/angelegte Einträge).
+func checkExplorerPath(path, projectRoot string, followLast bool) (string, error) {
+	if projectRoot != "" {
+		return resolveProjectPath(path, projectRoot, followLast)
+	}
+	if path == "" {
+		return "", fmt.Errorf("Pfad darf nicht leer sein")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("Ungültiger Pfad: %w", err)
+	}
+	return path, nil
+}
+
+// measureTree zählt Dateien und Bytes unterhalb von path (ohne Symlinks zu folgen).
+func measureTree(path string) (int, int64) {
+	files, size := 0, int64(0)
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		files++
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size
+}
+
+// moveTree verschiebt src an das freie Ziel dst. Scheitert das Umbenennen,
+// weil Quelle und Ziel auf verschiedenen Dateisystemen liegen, wird kopiert
+// und die Quelle danach gelöscht.
+func (c *fileCopier) moveTree(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil {
+		files, bytes := measureTree(dst)
+		c.advance(files, bytes, dst)
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("Verschieben fehlgeschlagen: %w", err)
+	}
+
+	if err := c.copyTree(src, dst); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("Quelle konnte nicht entfernt werden: %w", err)
+	}
+	return nil
+}
+
+// copyTree kopiert src rekursiv an das freie Ziel dst. Symlinks werden als
+// Symlinks kopiert, nicht verfolgt.
+func (c *fileCopier) copyTree(src, dst string) error {
+	if err := c.cancelled(); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		link, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(link, dst); err != nil {
+			return err
+		}
+		c.advance(1, 0, src)
+		return nil
+
+	case info.IsDir():
+		if err := os.Mkdir(dst, info.Mode().Perm()|0700); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := c.copyTree(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+				return err
+			}
+		}
+		return os.Chmod(dst, info.Mode().Perm())
+
+	case info.Mode().IsRegular():
+		return c.copyFile(src, dst, info.Mode().Perm())
+
+	default:
+		return fmt.Errorf("Dateityp wird nicht unterstützt: %s", src)
+	}
+}
+
+// copyFile kopiert eine einzelne Datei über eine temporäre Datei im
+// Zielordner, damit ein vorhandenes Ziel erst nach vollständiger Kopie
+// ersetzt wird.
+func (c *fileCopier) copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempFile := out.Name()
+
+	buf := make([]byte, fileCopyBufferSize)
+	for {
+		n, readErr := in.Read(buf)
+		if n > 0 {
+			if _, err := out.Write(buf[:n]); err != nil {
+				out.Close()
+				os.Remove(tempFile)
+				return err
+			}
+			c.advance(0, int64(n), src)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			err = readErr
+		} else {
+			err = c.cancelled()
+		}
+		if err != nil {
+			out.Close()
+			os.Remove(tempFile)
+			return err
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(tempFile)
+		return err
+	}
+	os.Chmod(tempFile, mode)
+	if err := os.Rename(tempFile, dst); err != nil {
+		os.Remove(tempFile)
+		return err
+	}
+	c.advance(1, 0, src)
+	return nil
+}
+
+// cancelled gibt einen Fehler zurück, wenn der Transfer abgebrochen wurde.
+func (c *fileCopier) cancelled() error {
+	if c == nil {
+		return nil
+	}
+	return c.ctx.Err()
+}
+
+// advance zählt den Fortschritt hoch und sendet ihn gedrosselt ans Frontend.
+func (c *fileCopier) advance(files int, bytes int64, current string) {
+	if c == nil {
+		return
+	}
+	c.progress.FilesDone += files
+	c.progress.BytesDone += bytes
+	c.progress.CurrentPath = current
+	if time.Since(c.lastEmit) >= fileTransferProgressInterval {
+		c.emit()
+	}
+}
+
+// emit sendet den aktuellen Fortschritt.
+func (c *fileCopier) emit() {
+	c.lastEmit = time.Now()
+	if c.app.ctx != nil {
+		wailsRuntime.EventsEmit(c.app.ctx, fmt.Sprintf("filetransfer_progress_%s", c.progress.TransferID), c.progress)
+	}
+}
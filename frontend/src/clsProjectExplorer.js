@@ -4,6 +4,7 @@
 import {
     CreateProject,
     OpenProject,
+    CloseProject,
     SelectProjectFolder,
     CheckProjectExists,
     ListProjectDirectory,
@@ -227,7 +228,11 @@ export class ProjectExplorer {
 
     async openProject(folderPath) {
         try {
-            const project = await OpenProject(folderPath);
+            const result = await OpenProject(folderPath);
+            const project = result.config;
+            for (const w of result.warnings) {
+                console.warn(`.leoedit.json: ${w.field}: ${w.message}`);
+            }
             this.project = project;
             this.currentPath = project.rootPath;
             this.onProjectChange(project);
@@ -254,6 +259,7 @@ export class ProjectExplorer {
     }
 
     closeProject() {
+        CloseProject();
         this.project = null;
         this.currentPath = '';
         this.entries = [];
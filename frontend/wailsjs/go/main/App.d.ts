@@ -8,6 +8,8 @@ export function AskGeminiForSuggestions(arg1:string,arg2:string,arg3:string):Pro
 
 export function CheckProjectExists(arg1:string):Promise<boolean>;
 
+export function CloseProject():Promise<void>;
+
 export function CreateProject(arg1:string,arg2:string):Promise<main.ProjectConfig>;
 
 export function DeleteFile(arg1:string):Promise<void>;
@@ -34,7 +36,7 @@ export function ListProjectDirectory(arg1:string,arg2:string):Promise<main.Direc
 
 export function LoadFile():Promise<main.FileResult>;
 
-export function OpenProject(arg1:string):Promise<main.ProjectConfig>;
+export function OpenProject(arg1:string):Promise<main.OpenProjectResult>;
 
 export function ProxyURL(arg1:string):Promise<string>;
 
@@ -14,6 +14,10 @@ export function CheckProjectExists(arg1) {
   return window['go']['main']['App']['CheckProjectExists'](arg1);
 }
 
+export function CloseProject() {
+  return window['go']['main']['App']['CloseProject']();
+}
+
 export function CreateProject(arg1, arg2) {
   return window['go']['main']['App']['CreateProject'](arg1, arg2);
 }
@@ -107,6 +107,24 @@ export namespace main {
 	        this.error = source["error"];
 	    }
 	}
+	export class ProjectConfigIssue {
+	    field: string;
+	    line: number;
+	    column: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectConfigIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.message = source["message"];
+	    }
+	}
 	export class ProjectConfig {
 	    name: string;
 	    rootPath: string;
@@ -127,6 +145,38 @@ export namespace main {
 	        this.lastOpened = source["lastOpened"];
 	    }
 	}
+	export class OpenProjectResult {
+	    config?: ProjectConfig;
+	    warnings: ProjectConfigIssue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OpenProjectResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.config = this.convertValues(source["config"], ProjectConfig);
+	        this.warnings = this.convertValues(source["warnings"], ProjectConfigIssue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class RecentProject {
 	    name: string;
 	    path: string;
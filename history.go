@@ -0,0 +1,381 @@
+// history.go — Lokale Dateihistorie (Zeitleiste) für jede Speicherung.
+// Vor jedem erfolgreichen Speichern wird der bisherige Inhalt der Datei
+// gzip-komprimiert unter <Konfigurationsordner>/Leoedit/history/ abgelegt,
+// getrennt nach Projekt (Projekt- bzw. Git-Stamm, siehe findFilterRoot):
+//   history/<Projekt>/<Datei>/path           → Pfad der Datei (zur Zuordnung)
+//   history/<Projekt>/<Datei>/<Zeit>-<Größe>-<Hash>.gz
+//
+// Alte Versionen werden nach Anzahl pro Datei, Alter und Gesamtgröße pro
+// Projekt entfernt. So gibt es "Rückgängig über Neustarts hinweg" auch
+// ohne git.
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.GetFileHistory(path)                  → Versionen, neueste zuerst
+//   window.go.main.App.GetFileRevision(path, revisionId)     → Inhalt einer Version
+//   window.go.main.App.DiffFileRevisions(path, fromId, toId) → Zeilen-Diff ("current" = Datei)
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	historyMaxRevisions   = 50                  // Versionen pro Datei
+	historyMaxAge         = 30 * 24 * time.Hour // Ältere Versionen werden gelöscht
+	historyMaxProjectSize = 100 * 1024 * 1024   // Komprimierte Größe pro Projekt
+	historyMaxFileSize    = 5 * 1024 * 1024     // Größere Dateien bekommen keine Historie
+	historyPathFile       = "path"
+	historyCurrentID      = "current"
+)
+
+// FileRevision beschreibt eine gespeicherte Version einer Datei.
+// Timestamp ist die Änderungszeit der Datei, bevor sie überschrieben wurde.
+type FileRevision struct {
+	ID        string `json:"id"`
+	Timestamp string `json:"timestamp"` // RFC3339
+	Size      int64  `json:"size"`      // Unkomprimiert
+	Hash      string `json:"hash"`      // Anfang des SHA-256 (hex)
+}
+
+// FileHistoryResult: Ergebnis von GetFileHistory.
+type FileHistoryResult struct {
+	Path      string         `json:"path"`
+	Revisions []FileRevision `json:"revisions"`
+	Error     string         `json:"error"`
+}
+
+// FileDiffResult: Ergebnis von DiffFileRevisions.
+type FileDiffResult struct {
+	Path      string     `json:"path"`
+	FromID    string     `json:"fromId"`
+	ToID      string     `json:"toId"`
+	Hunks     []DiffHunk `json:"hunks"`
+	Added     int        `json:"added"`
+	Removed   int        `json:"removed"`
+	Identical bool       `json:"identical"`
+	Error     string     `json:"error"`
+}
+
+// historyMu schützt den Historien-Ordner (Schreiben und Aufräumen)
+var historyMu sync.Mutex
+
+// GetFileHistory listet die gespeicherten Versionen einer Datei auf.
+func (a *App) GetFileHistory(path string) FileHistoryResult {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	dir, err := historyFileDir(path)
+	if err != nil {
+		return FileHistoryResult{Path: path, Error: err.Error()}
+	}
+	revisions, err := listRevisions(dir)
+	if err != nil {
+		return FileHistoryResult{Path: path, Error: err.Error()}
+	}
+
+	// Neueste zuerst
+	for i, j := 0, len(revisions)-1; i < j; i, j = i+1, j-1 {
+		revisions[i], revisions[j] = revisions[j], revisions[i]
+	}
+	return FileHistoryResult{Path: path, Revisions: revisions}
+}
+
+// GetFileRevision liefert den Inhalt einer Version, dekodiert wie beim
+// Öffnen der Datei (siehe textEncoding.go).
+func (a *App) GetFileRevision(path, revisionID string) FileResult {
+	data, err := readRevision(path, revisionID)
+	if err != nil {
+		return FileResult{Filename: path, Error: err.Error()}
+	}
+	content, format := decodeTextFile(data)
+	return FileResult{Content: content, Filename: path, Format: format}
+}
+
+// DiffFileRevisions vergleicht zwei Versionen einer Datei. "current" (oder
+// ein leerer String) steht für den aktuellen Inhalt auf der Festplatte.
+func (a *App) DiffFileRevisions(path, fromID, toID string) FileDiffResult {
+	result := FileDiffResult{Path: path, FromID: fromID, ToID: toID}
+
+	fromData, err := readRevision(path, fromID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	toData, err := readRevision(path, toID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	fromText, _ := decodeTextFile(fromData)
+	toText, _ := decodeTextFile(toData)
+	result.Hunks, result.Added, result.Removed = diffTexts(fromText, toText)
+	result.Identical = bytes.Equal(fromData, toData)
+	return result
+}
+
+// recordFileHistory sichert den aktuellen Inhalt von filename, bevor die
+// Datei überschrieben wird. Fehler werden nur protokolliert, damit das
+// Speichern selbst nie an der Historie scheitert.
+func recordFileHistory(filename string) {
+	info, err := os.Stat(filename)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > historyMaxFileSize {
+		return // Neue Datei oder zu groß
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	if err := storeRevision(filename, data, info.ModTime()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Dateihistorie für %s nicht gespeichert: %v\n", filename, err)
+	}
+}
+
+// storeRevision legt data als neue Version ab und räumt danach auf.
+// Ist die letzte Version identisch, wird nichts gespeichert.
+func storeRevision(filename string, data []byte, modTime time.Time) error {
+	dir, err := historyFileDir(filename)
+	if err != nil {
+		return err
+	}
+	revisions, err := listRevisions(dir)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256String(string(data))[:16]
+	if len(revisions) > 0 && revisions[len(revisions)-1].Hash == hash {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	pathFile := filepath.Join(dir, historyPathFile)
+	if _, err := os.Stat(pathFile); os.IsNotExist(err) {
+		os.WriteFile(pathFile, []byte(filepath.Clean(filename)), 0600)
+	}
+
+	// Gleicher Zeitstempel (z.B. mehrfach in derselben Sekunde gespeichert
+	// auf Dateisystemen mit grober Auflösung): nach hinten verschieben
+	stamp := modTime.UnixNano()
+	if len(revisions) > 0 {
+		if last := revisionStamp(revisions[len(revisions)-1].ID); stamp <= last {
+			stamp = last + 1
+		}
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(data)
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%d-%s.gz", stamp, len(data), hash)
+	if err := writeFileAtomic(filepath.Join(dir, name), buf.Bytes()); err != nil {
+		return err
+	}
+
+	pruneFileHistory(dir)
+	pruneProjectHistory(filepath.Dir(dir))
+	return nil
+}
+
+// readRevision liefert den unkomprimierten Inhalt einer Version bzw. bei
+// "current" den Inhalt der Datei.
+func readRevision(path, revisionID string) ([]byte, error) {
+	if revisionID == "" || revisionID == historyCurrentID {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Fehler beim Lesen: %w", err)
+		}
+		return data, nil
+	}
+
+	if strings.ContainsAny(revisionID, `/\`) || revisionStamp(revisionID) == 0 {
+		return nil, fmt.Errorf("Ungültige Version: %s", revisionID)
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	dir, err := historyFileDir(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filepath.Join(dir, revisionID+".gz"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("Version nicht gefunden: %s", revisionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("Version ist beschädigt: %w", err)
+	}
+	defer zr.Close()
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("Version ist beschädigt: %w", err)
+	}
+	return data, nil
+}
+
+// historyFileDir gibt den Historien-Ordner einer Datei zurück
+// (history/<Projekt>/<Datei>). Der Ordner wird nicht angelegt.
+func historyFileDir(filename string) (string, error) {
+	if filename == "" {
+		return "", fmt.Errorf("Dateiname darf nicht leer sein")
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := getAppDataDir("history")
+	if err != nil {
+		return "", fmt.Errorf("Dateihistorie nicht verfügbar: %w", err)
+	}
+	os.Chmod(root, 0700) // Versionen können vertrauliche Inhalte enthalten
+
+	project := findFilterRoot(filepath.Dir(abs))
+	return filepath.Join(root, sha256String(project)[:16], sha256String(abs)[:16]), nil
+}
+
+// listRevisions liest die Versionen in dir, älteste zuerst.
+// Ein fehlender Ordner ergibt eine leere Liste.
+func listRevisions(dir string) ([]FileRevision, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []FileRevision{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []FileRevision{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".gz")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		parts := strings.Split(id, "-")
+		if len(parts) != 3 {
+			continue
+		}
+		stamp, err1 := strconv.ParseInt(parts[0], 10, 64)
+		size, err2 := strconv.ParseInt(parts[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		revisions = append(revisions, FileRevision{
+			ID:        id,
+			Timestamp: time.Unix(0, stamp).Format(time.RFC3339),
+			Size:      size,
+			Hash:      parts[2],
+		})
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisionStamp(revisions[i].ID) < revisionStamp(revisions[j].ID)
+	})
+	return revisions, nil
+}
+
+// revisionStamp liest den Zeitstempel (UnixNano) aus einer Versions-ID.
+// Gibt 0 zurück, wenn die ID ungültig ist.
+func revisionStamp(id string) int64 {
+	prefix, _, _ := strings.Cut(id, "-")
+	stamp, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return stamp
+}
+
+// pruneFileHistory entfernt zu alte Versionen und hält die Anzahl pro
+// Datei bei historyMaxRevisions.
+func pruneFileHistory(dir string) {
+	revisions, err := listRevisions(dir)
+	if err != nil {
+		return
+	}
+
+	cutoff := time.Now().Add(-historyMaxAge).UnixNano()
+	excess := len(revisions) - historyMaxRevisions
+	for i, rev := range revisions {
+		if i < excess || revisionStamp(rev.ID) < cutoff {
+			os.Remove(filepath.Join(dir, rev.ID+".gz"))
+		}
+	}
+}
+
+// pruneProjectHistory löscht die ältesten Versionen des Projekts, bis die
+// komprimierte Gesamtgröße unter historyMaxProjectSize liegt. Ordner ohne
+// Versionen werden entfernt.
+func pruneProjectHistory(projectDir string) {
+	type storedRevision struct {
+		path  string
+		stamp int64
+		size  int64
+	}
+
+	dirs, err := os.ReadDir(projectDir)
+	if err != nil {
+		return
+	}
+
+	var all []storedRevision
+	var total int64
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(projectDir, d.Name())
+		revisions, _ := listRevisions(dir)
+		if len(revisions) == 0 {
+			os.RemoveAll(dir)
+			continue
+		}
+		for _, rev := range revisions {
+			path := filepath.Join(dir, rev.ID+".gz")
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			all = append(all, storedRevision{path: path, stamp: revisionStamp(rev.ID), size: info.Size()})
+			total += info.Size()
+		}
+	}
+
+	if total <= historyMaxProjectSize {
+		return
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].stamp < all[j].stamp })
+	for _, rev := range all {
+		if total <= historyMaxProjectSize {
+			break
+		}
+		if os.Remove(rev.path) == nil {
+			total -= rev.size
+		}
+	}
+}
@@ -0,0 +1,374 @@
+// ignore.go — .gitignore-kompatibler Pfadfilter.
+// Wird von der Suche (walkSearchableFiles) und vom Project Explorer
+// (ListProjectDirectory) verwendet, damit beide dieselben Dateien ausblenden.
+//
+// Unterstützt wird die gitignore-Syntax:
+//   - verschachtelte .gitignore-Dateien (gelten für ihren Ordner und darunter)
+//   - Negation mit "!" (spätere Regeln überschreiben frühere)
+//   - "**" für beliebig viele Ordnerebenen, "*", "?" und [Zeichenklassen]
+//   - "/" am Anfang verankert, "/" am Ende trifft nur Ordner
+//
+// Zusätzlich können in der .leoedit.json eigene include/exclude-Globs
+// hinterlegt werden (siehe ProjectConfig).
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Ordner, die bei der Suche standardmäßig übersprungen werden.
+// Sie haben die niedrigste Priorität und können per .gitignore oder
+// Projekt-Exclude mit "!" wieder eingeschlossen werden.
+var defaultSearchExcludes = []string{
+	"node_modules/", ".git/", ".svn/", ".hg/",
+	"vendor/", "dist/", "build/", ".next/",
+	"__pycache__/", ".pytest_cache/", ".tox/",
+	"target/", "bin/", "obj/",
+}
+
+// ignorePattern ist eine kompilierte Zeile aus einer .gitignore-Datei.
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool // "!pattern" schließt wieder ein
+	dirOnly bool // "pattern/" trifft nur Ordner
+}
+
+// pathFilter entscheidet, welche Pfade unterhalb von root ausgeblendet werden.
+// .gitignore-Dateien werden beim ersten Zugriff auf einen Ordner gelesen
+// und zwischengespeichert.
+type pathFilter struct {
+	root     string
+	defaults []ignorePattern
+	excludes []ignorePattern
+	includes []ignorePattern
+
+	mu       sync.Mutex
+	dirRules map[string][]ignorePattern // Ordner (relativ, mit "/") -> .gitignore-Regeln
+}
+
+// newPathFilter erstellt einen Filter für root.
+// defaults werden vor, excludes nach allen .gitignore-Regeln geprüft.
+// Ist includes nicht leer, gelten nur Dateien als sichtbar, die auf
+// mindestens ein include-Muster passen (Ordner sind davon ausgenommen).
+func newPathFilter(root string, defaults, excludes, includes []string) *pathFilter {
+	f := &pathFilter{
+		root:     filepath.Clean(root),
+		defaults: parseIgnorePatterns(defaults),
+		excludes: parseIgnorePatterns(excludes),
+		includes: parseIgnorePatterns(includes),
+		dirRules: make(map[string][]ignorePattern),
+	}
+
+	// .git/info/exclude gilt wie eine .gitignore im Wurzelordner
+	if lines, err := readIgnoreFile(filepath.Join(f.root, ".git", "info", "exclude")); err == nil {
+		f.dirRules[""] = append(parseIgnorePatterns(lines), f.loadDirRules("")...)
+	}
+
+	return f
+}
+
+// newProjectPathFilter erstellt einen Filter für einen beliebigen Pfad.
+// Als Wurzel dient der nächste übergeordnete Ordner mit .leoedit.json oder
+// .git, damit auch .gitignore-Dateien oberhalb von path greifen.
+// include/exclude aus der Projektkonfiguration werden übernommen.
+func newProjectPathFilter(path string, defaults []string) *pathFilter {
+	root := findFilterRoot(path)
+
+	var excludes, includes []string
+	if config, err := readProjectConfig(root); err == nil {
+		excludes = config.Exclude
+		includes = config.Include
+	}
+
+	return newPathFilter(root, defaults, excludes, includes)
+}
+
+// findFilterRoot sucht ab path aufwärts nach einem Projekt- oder Git-Stamm.
+// Wird keiner gefunden, ist path selbst die Wurzel.
+func findFilterRoot(path string) string {
+	path = filepath.Clean(path)
+	dir := path
+	for {
+		if _, err := os.Stat(filepath.Join(dir, projectConfigFile)); err == nil {
+			return dir
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return path
+		}
+		dir = parent
+	}
+}
+
+// isExcluded prüft, ob path ausgeblendet wird. Liegt ein übergeordneter
+// Ordner bereits auf der Ignore-Liste, ist auch path ausgeblendet
+// (wie bei git lässt sich das nicht per "!" aufheben).
+func (f *pathFilter) isExcluded(path string, isDir bool) bool {
+	rel, ok := f.relPath(path)
+	if !ok {
+		return false
+	}
+
+	parts := strings.Split(rel, "/")
+	for i := 1; i <= len(parts); i++ {
+		entryIsDir := i < len(parts) || isDir
+		if f.excludesEntry(strings.Join(parts[:i], "/"), entryIsDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIgnorePatterns prüft rel (relativ, mit "/" als Trenner) und seine
+// übergeordneten Ordner gegen patterns, ohne .gitignore-Dateien. Spätere
+// Muster überschreiben frühere; trifft ein Ordner, gilt auch sein Inhalt.
+func matchIgnorePatterns(patterns []ignorePattern, rel string, isDir bool) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+
+	parts := strings.Split(rel, "/")
+	for i := 1; i <= len(parts); i++ {
+		entryIsDir := i < len(parts) || isDir
+		sub := strings.Join(parts[:i], "/")
+		matched := false
+		for _, p := range patterns {
+			if p.dirOnly && !entryIsDir {
+				continue
+			}
+			if p.re.MatchString(sub) {
+				matched = !p.negate
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isIncluded prüft, ob eine Datei auf die include-Muster passt.
+// Ohne include-Muster ist jede Datei eingeschlossen.
+func (f *pathFilter) isIncluded(path string) bool {
+	if len(f.includes) == 0 {
+		return true
+	}
+	rel, ok := f.relPath(path)
+	if !ok {
+		return false
+	}
+	included := false
+	for _, p := range f.includes {
+		if !p.dirOnly && p.re.MatchString(rel) {
+			included = !p.negate
+		}
+	}
+	return included
+}
+
+// isHidden fasst isExcluded und isIncluded zusammen.
+func (f *pathFilter) isHidden(path string, isDir bool) bool {
+	if f.isExcluded(path, isDir) {
+		return true
+	}
+	return !isDir && !f.isIncluded(path)
+}
+
+// excludesEntry prüft einen einzelnen Eintrag, ohne die übergeordneten
+// Ordner zu betrachten. Für Verzeichnisdurchläufe, die ausgeblendete
+// Ordner ohnehin überspringen.
+// rel ist relativ zu root und verwendet "/" als Trenner.
+func (f *pathFilter) excludesEntry(rel string, isDir bool) bool {
+	excluded := false
+	apply := func(patterns []ignorePattern, subPath string) {
+		for _, p := range patterns {
+			if p.dirOnly && !isDir {
+				continue
+			}
+			if p.re.MatchString(subPath) {
+				excluded = !p.negate
+			}
+		}
+	}
+
+	apply(f.defaults, rel)
+
+	// .gitignore-Dateien vom Wurzelordner bis zum direkten Elternordner.
+	// Regeln tieferer Dateien überschreiben die höherer Ordner.
+	dir := ""
+	remaining := rel
+	for {
+		apply(f.rulesForDir(dir), remaining)
+
+		idx := strings.IndexByte(remaining, '/')
+		if idx < 0 {
+			break
+		}
+		if dir == "" {
+			dir = remaining[:idx]
+		} else {
+			dir = dir + "/" + remaining[:idx]
+		}
+		remaining = remaining[idx+1:]
+	}
+
+	apply(f.excludes, rel)
+	return excluded
+}
+
+// rulesForDir liefert die (zwischengespeicherten) .gitignore-Regeln eines Ordners.
+func (f *pathFilter) rulesForDir(dir string) []ignorePattern {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if rules, ok := f.dirRules[dir]; ok {
+		return rules
+	}
+	rules := f.loadDirRules(dir)
+	f.dirRules[dir] = rules
+	return rules
+}
+
+// loadDirRules liest die .gitignore eines Ordners (relativ zu root).
+func (f *pathFilter) loadDirRules(dir string) []ignorePattern {
+	lines, err := readIgnoreFile(filepath.Join(f.root, filepath.FromSlash(dir), ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	return parseIgnorePatterns(lines)
+}
+
+// relPath wandelt path in einen Pfad relativ zu root mit "/" als Trenner um.
+// ok ist false für root selbst und Pfade außerhalb von root.
+func (f *pathFilter) relPath(path string) (string, bool) {
+	rel, err := filepath.Rel(f.root, filepath.Clean(path))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// readIgnoreFile liest die Zeilen einer .gitignore-Datei.
+func readIgnoreFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// parseIgnorePatterns kompiliert eine Liste von gitignore-Zeilen.
+// Leere Zeilen, Kommentare und ungültige Muster werden übersprungen.
+func parseIgnorePatterns(lines []string) []ignorePattern {
+	var patterns []ignorePattern
+	for _, line := range lines {
+		if p, ok := parseIgnorePattern(line); ok {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// parseIgnorePattern kompiliert eine einzelne gitignore-Zeile.
+func parseIgnorePattern(line string) (ignorePattern, bool) {
+	line = strings.TrimSuffix(line, "\r")
+
+	// Abschließende Leerzeichen entfernen, außer sie sind mit "\" maskiert
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false
+	}
+
+	var p ignorePattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignorePattern{}, false
+	}
+
+	// Muster mit "/" gelten relativ zum Ordner der .gitignore,
+	// Muster ohne "/" treffen Namen in beliebiger Tiefe.
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		line = "**/" + line
+	}
+
+	re, err := compileIgnoreGlob(line)
+	if err != nil {
+		return ignorePattern{}, false
+	}
+	p.re = re
+	return p, true
+}
+
+// compileIgnoreGlob übersetzt ein gitignore-Glob in einen regulären Ausdruck,
+// der gegen einen relativen Pfad mit "/" als Trenner geprüft wird.
+func compileIgnoreGlob(glob string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	for i := 0; i < len(glob); {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			// Null oder mehr Ordnerebenen
+			sb.WriteString("(?:.*/)?")
+			i += 3
+		case glob[i:] == "**":
+			// Alles darunter
+			sb.WriteString(".*")
+			i += 2
+		case glob[i] == '*':
+			sb.WriteString("[^/]*")
+			i++
+		case glob[i] == '?':
+			sb.WriteString("[^/]")
+			i++
+		case glob[i] == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				i++
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 2
+		case glob[i] == '\\' && i+1 < len(glob):
+			sb.WriteString(regexp.QuoteMeta(glob[i+1 : i+2]))
+			i += 2
+		default:
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			i++
+		}
+	}
+
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
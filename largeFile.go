@@ -0,0 +1,584 @@
+// largeFile.go — Großdatei-Modus für Logs und andere sehr große Textdateien.
+// Statt die ganze Datei über die Wails-Bridge zu schicken, öffnet das
+// Frontend ein Handle und lädt nur die sichtbaren Zeilen nach:
+//   window.go.main.App.OpenLargeFile(path)              → Handle, Index startet im Hintergrund
+//   window.go.main.App.ReadLargeFileLines(id, start, n) → Zeilen start..start+n-1 (0-basiert)
+//   window.go.main.App.GetLargeFileInfo(id)             → Fortschritt, Zeilenanzahl
+//   window.go.main.App.StartLargeFileTail(id)           → Neue Zeilen laufend melden
+//   window.go.main.App.StopLargeFileTail(id)
+//   window.go.main.App.CloseLargeFile(id)
+//   window.go.main.App.ReadFileChunk(path, off, len)    → Byte-Bereich als Base64
+//
+// Events:
+//   largefile_progress_<id> → LargeFileInfo (während der Index aufgebaut wird)
+//   largefile_append_<id>   → LargeFileAppend (neue Zeilen im Tail-Modus)
+//
+// Der Zeilen-Index speichert nur jeden largeFileIndexStride-ten Zeilenanfang,
+// damit auch Dateien mit vielen Millionen Zeilen wenig Speicher brauchen.
+// Die Datei ist in diesem Modus schreibgeschützt.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// Ab dieser Größe lehnen ReadTextFile und ReadBinaryFile ab (TooLarge)
+	largeFileThreshold = 50 * 1024 * 1024
+
+	largeFileIndexStride   = 256             // Jeder n-te Zeilenanfang wird gespeichert
+	largeFileChunkSize     = 1024 * 1024     // Lesegröße beim Indexieren
+	largeFileMaxLines      = 10000           // Max. Zeilen pro ReadLargeFileLines
+	largeFileMaxLineLength = 64 * 1024       // Längere Zeilen werden gekürzt (Bytes)
+	largeFileMaxChunk      = 4 * 1024 * 1024 // Max. Bytes pro ReadFileChunk
+	largeFileMaxAppend     = 1000            // Max. Zeilen pro largefile_append-Event
+
+	largeFileProgressInterval = 200 * time.Millisecond
+	largeFileTailInterval     = 500 * time.Millisecond
+)
+
+// LargeFileInfo beschreibt eine im Großdatei-Modus geöffnete Datei.
+// LineCount zählt nur bereits indexierte Zeilen und wächst, solange
+// Indexing true ist.
+type LargeFileInfo struct {
+	FileID       string     `json:"fileId"`
+	Path         string     `json:"path"`
+	Size         int64      `json:"size"`
+	Format       TextFormat `json:"format"`
+	LineCount    int        `json:"lineCount"`
+	IndexedBytes int64      `json:"indexedBytes"`
+	Indexing     bool       `json:"indexing"`
+	Tailing      bool       `json:"tailing"`
+	Error        string     `json:"error"`
+}
+
+// LargeFileLines ist das Ergebnis von ReadLargeFileLines.
+type LargeFileLines struct {
+	StartLine int      `json:"startLine"` // 0-basiert
+	Lines     []string `json:"lines"`
+	LineCount int      `json:"lineCount"` // Bisher bekannte Gesamtzahl
+	Truncated bool     `json:"truncated"` // Mindestens eine Zeile wurde gekürzt
+}
+
+// LargeFileAppend wird im Tail-Modus als largefile_append_<id> gesendet.
+// Reset ist true, wenn die Datei gekürzt oder ersetzt wurde (z.B. Log-Rotation);
+// das Frontend sollte dann alle Zeilen neu laden.
+type LargeFileAppend struct {
+	FileID    string   `json:"fileId"`
+	StartLine int      `json:"startLine"`
+	Lines     []string `json:"lines"` // Bei mehr als largeFileMaxAppend Zeilen leer
+	LineCount int      `json:"lineCount"`
+	Size      int64    `json:"size"`
+	Reset     bool     `json:"reset"`
+}
+
+// FileChunkResult ist das Ergebnis von ReadFileChunk.
+type FileChunkResult struct {
+	Data   string `json:"data"` // Base64
+	Offset int64  `json:"offset"`
+	Length int    `json:"length"`
+	Size   int64  `json:"size"` // Gesamtgröße der Datei
+	EOF    bool   `json:"eof"`
+	Error  string `json:"error"`
+}
+
+// largeFile ist eine geöffnete Großdatei mit ihrem Zeilen-Index.
+type largeFile struct {
+	id     string
+	path   string
+	file   *os.File
+	format TextFormat
+	start  int64 // Offset nach einer BOM
+
+	mu           sync.RWMutex
+	checkpoints  []int64 // Offset von Zeile i*largeFileIndexStride
+	lines        int     // Vollständige (mit \n abgeschlossene) Zeilen
+	indexedBytes int64   // Bis hier ist der Index aufgebaut
+	indexing     bool
+	lastErr      string
+
+	cancel   context.CancelFunc // Beendet Index und Tail
+	tailStop chan struct{}      // nil, wenn kein Tail läuft
+}
+
+// largeFiles speichert alle geöffneten Großdateien (fileId -> Datei)
+var largeFiles = make(map[string]*largeFile)
+var largeFilesMu sync.Mutex
+var largeFileCounter atomic.Uint64
+
+// OpenLargeFile öffnet eine Datei im Großdatei-Modus. Der Zeilen-Index wird
+// im Hintergrund aufgebaut; Zeilen können schon währenddessen gelesen werden.
+func (a *App) OpenLargeFile(path string) (LargeFileInfo, error) {
+	if path == "" {
+		return LargeFileInfo{}, fmt.Errorf("Pfad darf nicht leer sein")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return LargeFileInfo{}, fmt.Errorf("Fehler beim Öffnen: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return LargeFileInfo{}, fmt.Errorf("Keine lesbare Datei: %s", path)
+	}
+
+	// Kodierung anhand des Dateianfangs erkennen
+	head := make([]byte, binarySniffLen)
+	n, _ := file.ReadAt(head, 0)
+	format := detectTextFormat(head[:n])
+	format.LineEnding, format.MixedLineEndings = detectLineEnding(decodeText(head[:n], format))
+	if format.Encoding == EncodingUTF16LE || format.Encoding == EncodingUTF16BE {
+		file.Close()
+		return LargeFileInfo{}, fmt.Errorf("UTF-16-Dateien werden im Großdatei-Modus nicht unterstützt")
+	}
+	if isBinaryContent(head[:n]) {
+		file.Close()
+		return LargeFileInfo{}, fmt.Errorf("Datei scheint binär zu sein: %s", path)
+	}
+
+	var start int64
+	if format.BOM {
+		start = int64(len(bomUTF8))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lf := &largeFile{
+		id:           fmt.Sprintf("file-%d", largeFileCounter.Add(1)),
+		path:         path,
+		file:         file,
+		format:       format,
+		start:        start,
+		checkpoints:  []int64{start},
+		indexedBytes: start,
+		indexing:     true,
+		cancel:       cancel,
+	}
+
+	largeFilesMu.Lock()
+	largeFiles[lf.id] = lf
+	largeFilesMu.Unlock()
+
+	go a.buildLargeFileIndex(ctx, lf)
+
+	return lf.info(), nil
+}
+
+// GetLargeFileInfo liefert den aktuellen Stand einer geöffneten Großdatei.
+func (a *App) GetLargeFileInfo(fileId string) (LargeFileInfo, error) {
+	lf, err := getLargeFile(fileId)
+	if err != nil {
+		return LargeFileInfo{}, err
+	}
+	return lf.info(), nil
+}
+
+// ReadLargeFileLines liest count Zeilen ab startLine (0-basiert).
+// Zeilen, die noch nicht indexiert sind, werden nicht geliefert.
+func (a *App) ReadLargeFileLines(fileId string, startLine, count int) (LargeFileLines, error) {
+	lf, err := getLargeFile(fileId)
+	if err != nil {
+		return LargeFileLines{}, err
+	}
+	if startLine < 0 || count < 0 {
+		return LargeFileLines{}, fmt.Errorf("Ungültiger Zeilenbereich")
+	}
+	count = min(count, largeFileMaxLines)
+
+	return lf.readLines(startLine, count)
+}
+
+// StartLargeFileTail meldet neue Zeilen, sobald die Datei wächst.
+func (a *App) StartLargeFileTail(fileId string) error {
+	lf, err := getLargeFile(fileId)
+	if err != nil {
+		return err
+	}
+
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if lf.tailStop != nil {
+		return nil // Läuft bereits
+	}
+	lf.tailStop = make(chan struct{})
+	go a.tailLargeFile(lf, lf.tailStop)
+	return nil
+}
+
+// StopLargeFileTail beendet den Tail-Modus.
+func (a *App) StopLargeFileTail(fileId string) error {
+	lf, err := getLargeFile(fileId)
+	if err != nil {
+		return err
+	}
+
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if lf.tailStop != nil {
+		close(lf.tailStop)
+		lf.tailStop = nil
+	}
+	return nil
+}
+
+// CloseLargeFile schließt eine Großdatei und gibt den Index frei.
+func (a *App) CloseLargeFile(fileId string) error {
+	largeFilesMu.Lock()
+	lf, exists := largeFiles[fileId]
+	delete(largeFiles, fileId)
+	largeFilesMu.Unlock()
+
+	if !exists {
+		return nil // Bereits geschlossen
+	}
+	lf.close()
+	return nil
+}
+
+// closeAllLargeFiles schließt alle Großdateien (beim Beenden der App).
+func closeAllLargeFiles() {
+	largeFilesMu.Lock()
+	defer largeFilesMu.Unlock()
+
+	for id, lf := range largeFiles {
+		lf.close()
+		delete(largeFiles, id)
+	}
+}
+
+// ReadFileChunk liest einen Byte-Bereich einer Datei als Base64, z.B. für
+// eine Hex-Ansicht großer Binärdateien.
+func (a *App) ReadFileChunk(path string, offset int64, length int) FileChunkResult {
+	if offset < 0 || length < 0 {
+		return FileChunkResult{Error: "Ungültiger Bereich"}
+	}
+	length = min(length, largeFileMaxChunk)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return FileChunkResult{Error: fmt.Sprintf("Fehler beim Lesen: %v", err)}
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return FileChunkResult{Error: fmt.Sprintf("Fehler beim Lesen: %v", err)}
+	}
+
+	buf := make([]byte, length)
+	n, err := file.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return FileChunkResult{Error: fmt.Sprintf("Fehler beim Lesen: %v", err)}
+	}
+
+	return FileChunkResult{
+		Data:   base64.StdEncoding.EncodeToString(buf[:n]),
+		Offset: offset,
+		Length: n,
+		Size:   info.Size(),
+		EOF:    offset+int64(n) >= info.Size(),
+	}
+}
+
+// getLargeFile sucht eine geöffnete Großdatei.
+func getLargeFile(fileId string) (*largeFile, error) {
+	largeFilesMu.Lock()
+	defer largeFilesMu.Unlock()
+
+	lf, exists := largeFiles[fileId]
+	if !exists {
+		return nil, fmt.Errorf("Datei %s ist nicht geöffnet", fileId)
+	}
+	return lf, nil
+}
+
+// close beendet Index und Tail und schließt das Dateihandle.
+func (lf *largeFile) close() {
+	lf.cancel()
+	lf.mu.Lock()
+	if lf.tailStop != nil {
+		close(lf.tailStop)
+		lf.tailStop = nil
+	}
+	lf.mu.Unlock()
+	lf.file.Close()
+}
+
+// info liefert den aktuellen Stand als LargeFileInfo.
+func (lf *largeFile) info() LargeFileInfo {
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+
+	size := lf.indexedBytes
+	if info, err := lf.file.Stat(); err == nil {
+		size = info.Size()
+	}
+	return LargeFileInfo{
+		FileID:       lf.id,
+		Path:         lf.path,
+		Size:         size,
+		Format:       lf.format,
+		LineCount:    lf.lineCountLocked(),
+		IndexedBytes: lf.indexedBytes,
+		Indexing:     lf.indexing,
+		Tailing:      lf.tailStop != nil,
+		Error:        lf.lastErr,
+	}
+}
+
+// lineCountLocked zählt die lesbaren Zeilen. Eine letzte Zeile ohne
+// Zeilenumbruch zählt erst, wenn der Index fertig ist und kein Tail läuft
+// (sonst könnte sie noch weiterwachsen). Aufrufer hält lf.mu.
+func (lf *largeFile) lineCountLocked() int {
+	if lf.indexing || lf.tailStop != nil || lf.indexedBytes <= lf.start {
+		return lf.lines
+	}
+	last := make([]byte, 1)
+	if _, err := lf.file.ReadAt(last, lf.indexedBytes-1); err == nil && last[0] != '\n' {
+		return lf.lines + 1
+	}
+	return lf.lines
+}
+
+// buildLargeFileIndex indexiert die Datei bis zum aktuellen Ende und meldet
+// dabei den Fortschritt.
+func (a *App) buildLargeFileIndex(ctx context.Context, lf *largeFile) {
+	lastProgress := time.Now()
+	err := lf.indexUntilEOF(ctx, func() {
+		if time.Since(lastProgress) >= largeFileProgressInterval {
+			a.emitLargeFileProgress(lf)
+			lastProgress = time.Now()
+		}
+	})
+	if ctx.Err() != nil {
+		return // Datei geschlossen
+	}
+
+	lf.mu.Lock()
+	lf.indexing = false
+	if err != nil {
+		lf.lastErr = "Indexfehler: " + err.Error()
+	}
+	lf.mu.Unlock()
+
+	a.emitLargeFileProgress(lf)
+}
+
+// emitLargeFileProgress sendet largefile_progress_<id>.
+func (a *App) emitLargeFileProgress(lf *largeFile) {
+	if a.ctx != nil {
+		wailsRuntime.EventsEmit(a.ctx, fmt.Sprintf("largefile_progress_%s", lf.id), lf.info())
+	}
+}
+
+// indexUntilEOF liest ab indexedBytes bis zum Dateiende und ergänzt den Index.
+// progress wird nach jedem Block aufgerufen.
+func (lf *largeFile) indexUntilEOF(ctx context.Context, progress func()) error {
+	buf := make([]byte, largeFileChunkSize)
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		lf.mu.RLock()
+		file, offset := lf.file, lf.indexedBytes
+		lf.mu.RUnlock()
+
+		n, err := file.ReadAt(buf, offset)
+		if n > 0 {
+			lf.addChunk(buf[:n], offset)
+			if progress != nil {
+				progress()
+			}
+		}
+		if err == io.EOF || n == 0 {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// addChunk zählt die Zeilenumbrüche eines Blocks und merkt sich jeden
+// largeFileIndexStride-ten Zeilenanfang.
+func (lf *largeFile) addChunk(chunk []byte, offset int64) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	pos := 0
+	for {
+		i := bytes.IndexByte(chunk[pos:], '\n')
+		if i < 0 {
+			break
+		}
+		pos += i + 1
+		lf.lines++
+		if lf.lines%largeFileIndexStride == 0 {
+			lf.checkpoints = append(lf.checkpoints, offset+int64(pos))
+		}
+	}
+	lf.indexedBytes = offset + int64(len(chunk))
+}
+
+// resetIndex verwirft den Index (Datei wurde gekürzt oder ersetzt).
+func (lf *largeFile) resetIndex() {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	lf.checkpoints = []int64{lf.start}
+	lf.lines = 0
+	lf.indexedBytes = lf.start
+}
+
+// readLines liest count Zeilen ab startLine ausgehend vom nächsten Checkpoint.
+func (lf *largeFile) readLines(startLine, count int) (LargeFileLines, error) {
+	lf.mu.RLock()
+	total := lf.lineCountLocked()
+	cp := startLine / largeFileIndexStride
+	var offset int64
+	if cp < len(lf.checkpoints) {
+		offset = lf.checkpoints[cp]
+	}
+	file, end := lf.file, lf.indexedBytes
+	lf.mu.RUnlock()
+
+	result := LargeFileLines{StartLine: startLine, Lines: []string{}, LineCount: total}
+	if startLine >= total || count == 0 {
+		return result, nil
+	}
+	count = min(count, total-startLine)
+
+	reader := bufio.NewReaderSize(io.NewSectionReader(file, offset, end-offset), 64*1024)
+	for i := cp * largeFileIndexStride; i < startLine; i++ {
+		if _, _, err := readCappedLine(reader); err != nil {
+			return result, fmt.Errorf("Fehler beim Lesen: %w", err)
+		}
+	}
+
+	for len(result.Lines) < count {
+		line, truncated, err := readCappedLine(reader)
+		if err == io.EOF && len(line) == 0 {
+			break
+		}
+		if err != nil && err != io.EOF {
+			return result, fmt.Errorf("Fehler beim Lesen: %w", err)
+		}
+		result.Lines = append(result.Lines, decodeText(line, lf.format))
+		result.Truncated = result.Truncated || truncated
+	}
+	return result, nil
+}
+
+// readCappedLine liest eine Zeile ohne Zeilenende. Zeilen über
+// largeFileMaxLineLength werden gekürzt, der Rest wird übersprungen.
+func readCappedLine(reader *bufio.Reader) ([]byte, bool, error) {
+	var line []byte
+	for {
+		part, err := reader.ReadSlice('\n')
+		// Platz für "\r\n", damit Zeilen mit genau der Maximallänge nicht als gekürzt gelten
+		if room := largeFileMaxLineLength + 2 - len(line); room > 0 {
+			line = append(line, part[:min(len(part), room)]...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(line) > largeFileMaxLineLength {
+			return line[:largeFileMaxLineLength], true, err
+		}
+		return line, false, err
+	}
+}
+
+// tailLargeFile prüft regelmäßig die Dateigröße und meldet neue Zeilen.
+func (a *App) tailLargeFile(lf *largeFile, stop chan struct{}) {
+	ticker := time.NewTicker(largeFileTailInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		lf.mu.RLock()
+		indexing := lf.indexing
+		indexed := lf.indexedBytes
+		before := lf.lines
+		lf.mu.RUnlock()
+		if indexing {
+			continue // Der erste Durchlauf ist noch nicht fertig
+		}
+
+		info, err := os.Stat(lf.path)
+		if err != nil {
+			continue // Datei kurzzeitig weg (z.B. Log-Rotation)
+		}
+
+		reset := false
+		if info.Size() < indexed || !sameFile(lf.file, info) {
+			// Gekürzt oder ersetzt: neu öffnen und von vorn indexieren
+			if file, err := os.Open(lf.path); err == nil {
+				lf.mu.Lock()
+				lf.file.Close()
+				lf.file = file
+				lf.mu.Unlock()
+			}
+			lf.resetIndex()
+			reset = true
+			before = 0
+		} else if info.Size() == indexed {
+			continue
+		}
+
+		if err := lf.indexUntilEOF(context.Background(), nil); err != nil {
+			continue
+		}
+
+		lf.mu.RLock()
+		after := lf.lines
+		size := lf.indexedBytes
+		lf.mu.RUnlock()
+		if after == before && !reset {
+			continue // Nur eine unvollständige Zeile kam hinzu
+		}
+
+		event := LargeFileAppend{
+			FileID:    lf.id,
+			StartLine: before,
+			Lines:     []string{},
+			LineCount: after,
+			Size:      size,
+			Reset:     reset,
+		}
+		if !reset && after-before <= largeFileMaxAppend {
+			if lines, err := lf.readLines(before, after-before); err == nil {
+				event.Lines = lines.Lines
+			}
+		}
+		if a.ctx != nil {
+			wailsRuntime.EventsEmit(a.ctx, fmt.Sprintf("largefile_append_%s", lf.id), event)
+		}
+	}
+}
+
+// sameFile prüft, ob das offene Handle noch auf die Datei unter dem Pfad zeigt.
+func sameFile(file *os.File, info os.FileInfo) bool {
+	current, err := file.Stat()
+	return err == nil && os.SameFile(current, info)
+}
@@ -0,0 +1,510 @@
+// problemMatcher.go — Fehler und Warnungen aus Build-Ausgaben erkennen.
+// Ein Problem-Matcher zerlegt die Ausgabe eines Tasks (siehe taskRunner.go)
+// oder des Terminals zeilenweise in Problem-Einträge (Datei, Zeile, Spalte,
+// Schweregrad, Meldung), die das Frontend im Lint-Gutter anzeigt.
+//
+// Eingebaute Matcher: gcc (auch clang), go, tsc, eslint, python.
+// Eigene Matcher stehen in der .leoedit.json unter "problemMatchers":
+//
+//	"problemMatchers": [
+//	  {"name": "mylint", "pattern": "^(?P<file>[^:]+):(?P<line>\\d+): (?P<message>.+)$", "severity": "warning"}
+//	]
+//
+// Benannte Gruppen im Regex: file, line, column, severity, code, message.
+// Ein Pattern mit file, aber ohne message merkt sich nur die Datei für die
+// folgenden Zeilen (wie die Ausgabe von eslint).
+//
+// Probleme werden pro Projekt und Quelle (Task-Name bzw. "terminal")
+// gespeichert; jede Änderung wird gesendet:
+//   problems_changed → ProblemsUpdate (alle Probleme des Projekts)
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.GetProblemMatchers(projectRoot)          → Namen
+//   window.go.main.App.GetProblems(projectRoot)                 → []Problem
+//   window.go.main.App.ClearProblems(projectRoot, source)       // source leer = alle
+//   window.go.main.App.ParseProblemOutput(projectRoot, matcher, output) → []Problem
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Maximale Anzahl Probleme pro Quelle (weitere werden verworfen)
+const problemMaxPerSource = 1000
+
+// Quelle für Probleme aus dem Terminal (siehe ParseProblemOutput)
+const problemSourceTerminal = "terminal"
+
+// Problem ist ein erkannter Fehler bzw. eine Warnung. File ist absolut,
+// Line und Column beginnen bei 1 (0 = unbekannt).
+type Problem struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"` // "error", "warning" oder "info"
+	Message  string `json:"message"`
+	Code     string `json:"code,omitempty"` // z.B. "TS2304" oder Name der eslint-Regel
+	Source   string `json:"source"`         // Task-Name oder "terminal"
+}
+
+// ProblemsUpdate wird als problems_changed gesendet.
+type ProblemsUpdate struct {
+	ProjectRoot string    `json:"projectRoot"`
+	Problems    []Problem `json:"problems"`
+}
+
+// ProblemMatcherConfig beschreibt einen eigenen Matcher in der .leoedit.json.
+// Severity gilt, wenn das Pattern keine Gruppe severity hat (Standard
+// "error"). Passt ResetPattern auf eine Zeile, werden die bisher gefundenen
+// Probleme verworfen (z.B. bei einem neuen Durchlauf im Watch-Modus).
+type ProblemMatcherConfig struct {
+	Name         string   `json:"name"`
+	Pattern      string   `json:"pattern,omitempty"`
+	Patterns     []string `json:"patterns,omitempty"` // Mehrere Patterns, das erste passende gilt
+	Severity     string   `json:"severity,omitempty"`
+	ResetPattern string   `json:"resetPattern,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Unbekannte Schlüssel (siehe projectSchema.go)
+}
+
+// problemMatcher ist ein kompilierter Matcher.
+// traceback aktiviert die Auswertung von Python-Tracebacks, die sich
+// nicht mit einzelnen Zeilen-Patterns beschreiben lassen.
+type problemMatcher struct {
+	name      string
+	patterns  []*regexp.Regexp
+	severity  string
+	reset     *regexp.Regexp
+	traceback bool
+}
+
+// Dateipfad in Patterns: optional mit Laufwerksbuchstabe (Windows)
+const problemFilePattern = `(?P<file>(?:[A-Za-z]:)?[^:\s][^:]*)`
+
+// builtinProblemMatchers sind die eingebauten Matcher (Name -> Matcher)
+var builtinProblemMatchers = map[string]*problemMatcher{
+	"gcc": {
+		name: "gcc",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`^` + problemFilePattern + `:(?P<line>\d+):(?:(?P<column>\d+):)? (?:fatal )?(?P<severity>error|warning|note): (?P<message>.+)$`),
+		},
+		severity: "error",
+	},
+	"go": {
+		name: "go",
+		patterns: []*regexp.Regexp{
+			// go build/vet: ./main.go:12:5: undefined: x; go test: "    x_test.go:12: msg"
+			regexp.MustCompile(`^\s*(?:vet: )?(?P<file>(?:[A-Za-z]:)?[^:\s][^:]*\.go):(?P<line>\d+)(?::(?P<column>\d+))?: (?P<message>.+)$`),
+		},
+		severity: "error",
+	},
+	"tsc": {
+		name: "tsc",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`^(?P<file>[^\s(][^(]*)\((?P<line>\d+),(?P<column>\d+)\): (?P<severity>error|warning|message) (?P<code>TS\d+): (?P<message>.+)$`),
+			regexp.MustCompile(`^` + problemFilePattern + `:(?P<line>\d+):(?P<column>\d+) - (?P<severity>error|warning|message) (?P<code>TS\d+): (?P<message>.+)$`),
+		},
+		severity: "error",
+		reset:    regexp.MustCompile(`Starting (?:incremental )?compilation`),
+	},
+	"eslint": {
+		name: "eslint",
+		patterns: []*regexp.Regexp{
+			// Format "compact"
+			regexp.MustCompile(`^(?P<file>.+): line (?P<line>\d+), col (?P<column>\d+), (?P<severity>Error|Warning) - (?P<message>.+?)(?: \((?P<code>[^)]+)\))?$`),
+			// Format "stylish": Dateiname allein, dann eingerückte Einträge
+			regexp.MustCompile(`^(?P<file>(?:[A-Za-z]:)?[/\\]\S.*)$`),
+			regexp.MustCompile(`^\s+(?P<line>\d+):(?P<column>\d+)\s+(?P<severity>error|warning)\s+(?P<message>.+?)(?:\s{2,}(?P<code>\S+))?$`),
+		},
+		severity: "error",
+	},
+	"python": {
+		name:      "python",
+		severity:  "error",
+		traceback: true,
+	},
+}
+
+var (
+	pythonTracebackStart = regexp.MustCompile(`^Traceback \(most recent call last\):`)
+	pythonTracebackFrame = regexp.MustCompile(`^\s+File "(?P<file>[^"]+)", line (?P<line>\d+)`)
+	pythonException      = regexp.MustCompile(`^(?P<code>[A-Za-z_][\w.]*)(?::\s*(?P<message>.*))?$`)
+	ansiEscape           = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+)
+
+// projectProblems speichert die Probleme pro Projekt und Quelle
+// (projectRoot -> source -> Probleme)
+var projectProblems = make(map[string]map[string][]Problem)
+var projectProblemsMu sync.Mutex
+
+// GetProblemMatchers liefert die Namen aller im Projekt verfügbaren
+// Matcher (eingebaut und aus der .leoedit.json), z.B. für eine Auswahlliste.
+func (a *App) GetProblemMatchers(projectRoot string) []string {
+	names := make(map[string]bool)
+	for name := range builtinProblemMatchers {
+		names[name] = true
+	}
+	if config, err := readProjectConfig(projectRoot); err == nil {
+		for _, m := range config.ProblemMatchers {
+			if m.Name != "" {
+				names[m.Name] = true
+			}
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GetProblems liefert alle gespeicherten Probleme eines Projekts,
+// sortiert nach Datei und Position.
+func (a *App) GetProblems(projectRoot string) []Problem {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return []Problem{}
+	}
+
+	projectProblemsMu.Lock()
+	defer projectProblemsMu.Unlock()
+	return collectProblems(root)
+}
+
+// ClearProblems verwirft die Probleme einer Quelle (leer = alle Quellen).
+func (a *App) ClearProblems(projectRoot, source string) {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return
+	}
+
+	projectProblemsMu.Lock()
+	if source == "" {
+		delete(projectProblems, root)
+	} else if sources := projectProblems[root]; sources != nil {
+		delete(sources, source)
+	}
+	update := ProblemsUpdate{ProjectRoot: root, Problems: collectProblems(root)}
+	projectProblemsMu.Unlock()
+
+	a.emitProblems(update)
+}
+
+// ParseProblemOutput wertet Ausgabe aus dem Terminal mit dem Matcher
+// matcherName aus. Relative Pfade gelten relativ zum Projektstamm.
+// Die Probleme ersetzen die bisherigen Terminal-Probleme des Projekts.
+func (a *App) ParseProblemOutput(projectRoot, matcherName, output string) ([]Problem, error) {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("Ungültiger Projektstamm: %w", err)
+	}
+
+	var custom []ProblemMatcherConfig
+	if config, err := readProjectConfig(root); err == nil {
+		custom = config.ProblemMatchers
+	}
+	matcher, err := findProblemMatcher(matcherName, custom)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := newProblemParser(matcher, problemSourceTerminal, root, root)
+	parser.feed("terminal", output)
+	parser.finish()
+
+	a.setProblems(root, problemSourceTerminal, parser.problems)
+	return parser.problems, nil
+}
+
+// setProblems ersetzt die Probleme einer Quelle und sendet problems_changed.
+func (a *App) setProblems(root, source string, problems []Problem) {
+	projectProblemsMu.Lock()
+	sources := projectProblems[root]
+	if sources == nil {
+		sources = make(map[string][]Problem)
+		projectProblems[root] = sources
+	}
+	sources[source] = append([]Problem(nil), problems...)
+	update := ProblemsUpdate{ProjectRoot: root, Problems: collectProblems(root)}
+	projectProblemsMu.Unlock()
+
+	a.emitProblems(update)
+}
+
+func (a *App) emitProblems(update ProblemsUpdate) {
+	if a.ctx != nil {
+		wailsRuntime.EventsEmit(a.ctx, "problems_changed", update)
+	}
+}
+
+// collectProblems fasst alle Quellen eines Projekts zusammen. Muss mit
+// gesperrtem projectProblemsMu aufgerufen werden.
+func collectProblems(root string) []Problem {
+	result := []Problem{}
+	for _, problems := range projectProblems[root] {
+		result = append(result, problems...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		if result[i].Line != result[j].Line {
+			return result[i].Line < result[j].Line
+		}
+		return result[i].Column < result[j].Column
+	})
+	return result
+}
+
+// findProblemMatcher sucht einen Matcher; eigene Matcher aus der
+// .leoedit.json haben Vorrang vor gleichnamigen eingebauten.
+func findProblemMatcher(name string, custom []ProblemMatcherConfig) (*problemMatcher, error) {
+	for _, config := range custom {
+		if config.Name == name {
+			return compileProblemMatcher(config)
+		}
+	}
+	if matcher, ok := builtinProblemMatchers[name]; ok {
+		return matcher, nil
+	}
+	return nil, fmt.Errorf("Problem-Matcher nicht gefunden: %s", name)
+}
+
+// compileProblemMatcher übersetzt einen eigenen Matcher.
+func compileProblemMatcher(config ProblemMatcherConfig) (*problemMatcher, error) {
+	sources := config.Patterns
+	if config.Pattern != "" {
+		sources = append([]string{config.Pattern}, sources...)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("Problem-Matcher %s hat kein Pattern", config.Name)
+	}
+
+	matcher := &problemMatcher{name: config.Name, severity: normalizeSeverity(config.Severity, "error")}
+	for _, source := range sources {
+		re, err := regexp.Compile(source)
+		if err != nil {
+			return nil, fmt.Errorf("Ungültiges Pattern in Problem-Matcher %s: %w", config.Name, err)
+		}
+		if re.SubexpIndex("file") < 0 && re.SubexpIndex("message") < 0 {
+			return nil, fmt.Errorf("Pattern in Problem-Matcher %s braucht eine Gruppe file oder message", config.Name)
+		}
+		matcher.patterns = append(matcher.patterns, re)
+	}
+	if config.ResetPattern != "" {
+		re, err := regexp.Compile(config.ResetPattern)
+		if err != nil {
+			return nil, fmt.Errorf("Ungültiges resetPattern in Problem-Matcher %s: %w", config.Name, err)
+		}
+		matcher.reset = re
+	}
+	return matcher, nil
+}
+
+// problemParser wendet einen Matcher zeilenweise auf Ausgabe an.
+// Nicht threadsicher; der Aufrufer muss Zugriffe serialisieren.
+type problemParser struct {
+	matcher  *problemMatcher
+	source   string
+	dir      string // Basis für relative Pfade
+	root     string
+	problems []Problem
+
+	partial map[string]string // Angefangene Zeile pro Ausgabekanal
+	file    string            // Aktuelle Datei für Patterns ohne file
+	frames  []Problem         // Frames des aktuellen Python-Tracebacks
+}
+
+func newProblemParser(matcher *problemMatcher, source, dir, root string) *problemParser {
+	return &problemParser{
+		matcher:  matcher,
+		source:   source,
+		dir:      dir,
+		root:     root,
+		problems: []Problem{},
+		partial:  make(map[string]string),
+	}
+}
+
+// feed verarbeitet ein Stück Ausgabe eines Kanals. Unvollständige Zeilen
+// werden bis zum nächsten Aufruf zurückgehalten. Gibt true zurück, wenn
+// sich die Liste der Probleme geändert hat.
+func (p *problemParser) feed(stream, data string) bool {
+	text := p.partial[stream] + data
+	lines := strings.Split(text, "\n")
+	p.partial[stream] = lines[len(lines)-1]
+
+	changed := false
+	for _, line := range lines[:len(lines)-1] {
+		if p.matchLine(line) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// finish verarbeitet zurückgehaltene Zeilen am Ende der Ausgabe.
+func (p *problemParser) finish() bool {
+	changed := false
+	streams := make([]string, 0, len(p.partial))
+	for stream := range p.partial {
+		streams = append(streams, stream)
+	}
+	sort.Strings(streams)
+	for _, stream := range streams {
+		if line := p.partial[stream]; line != "" && p.matchLine(line) {
+			changed = true
+		}
+		delete(p.partial, stream)
+	}
+	return changed
+}
+
+// matchLine wertet eine Zeile aus und gibt true zurück, wenn sich die
+// Liste der Probleme geändert hat.
+func (p *problemParser) matchLine(line string) bool {
+	line = strings.TrimRight(line, "\r")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:] // Fortschrittsanzeigen überschreiben die Zeile
+	}
+	line = ansiEscape.ReplaceAllString(line, "")
+
+	if p.matcher.reset != nil && p.matcher.reset.MatchString(line) {
+		p.file = ""
+		if len(p.problems) == 0 {
+			return false
+		}
+		p.problems = []Problem{}
+		return true
+	}
+	if p.matcher.traceback {
+		return p.matchTraceback(line)
+	}
+
+	for _, re := range p.matcher.patterns {
+		match := re.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		group := func(name string) string {
+			if i := re.SubexpIndex(name); i >= 0 {
+				return strings.TrimSpace(match[i])
+			}
+			return ""
+		}
+
+		file := group("file")
+		if re.SubexpIndex("message") < 0 {
+			p.file = file
+			return false
+		}
+		if file == "" {
+			file = p.file
+		}
+		if file == "" {
+			return false
+		}
+		line, _ := strconv.Atoi(group("line"))
+		column, _ := strconv.Atoi(group("column"))
+		return p.add(Problem{
+			File:     file,
+			Line:     line,
+			Column:   column,
+			Severity: normalizeSeverity(group("severity"), p.matcher.severity),
+			Message:  group("message"),
+			Code:     group("code"),
+		})
+	}
+	return false
+}
+
+// matchTraceback wertet Python-Tracebacks aus. Gemeldet wird die
+// Ausnahme am letzten Frame im Projekt (sonst am letzten Frame überhaupt),
+// da Frames aus Bibliotheken im Editor meist nicht weiterhelfen.
+func (p *problemParser) matchTraceback(line string) bool {
+	if pythonTracebackStart.MatchString(line) {
+		p.frames = nil
+		return false
+	}
+	if match := pythonTracebackFrame.FindStringSubmatch(line); match != nil {
+		lineNo, _ := strconv.Atoi(match[2])
+		p.frames = append(p.frames, Problem{File: match[1], Line: lineNo})
+		return false
+	}
+	if len(p.frames) == 0 || line == "" || line[0] == ' ' || line[0] == '\t' {
+		return false
+	}
+
+	// Erste nicht eingerückte Zeile nach den Frames: die Ausnahme
+	// (SyntaxError wird auch ohne "Traceback" mit einem Frame gemeldet)
+	match := pythonException.FindStringSubmatch(line)
+	frames := p.frames
+	p.frames = nil
+	if match == nil {
+		return false
+	}
+
+	frame := frames[len(frames)-1]
+	for i := len(frames) - 1; i >= 0; i-- {
+		if isPathWithinRoot(p.resolveFile(frames[i].File), p.root) {
+			frame = frames[i]
+			break
+		}
+	}
+	message := match[1]
+	if match[2] != "" {
+		message += ": " + match[2]
+	}
+	frame.Severity = p.matcher.severity
+	frame.Message = message
+	frame.Code = match[1]
+	return p.add(frame)
+}
+
+// add ergänzt ein Problem (mit absolutem Pfad) und gibt false zurück,
+// wenn das Limit erreicht ist.
+func (p *problemParser) add(problem Problem) bool {
+	if len(p.problems) >= problemMaxPerSource {
+		return false
+	}
+	problem.File = p.resolveFile(problem.File)
+	problem.Source = p.source
+	p.problems = append(p.problems, problem)
+	return true
+}
+
+// resolveFile macht einen Pfad aus der Ausgabe absolut.
+func (p *problemParser) resolveFile(file string) string {
+	if filepath.IsAbs(file) {
+		return filepath.Clean(file)
+	}
+	return filepath.Join(p.dir, file)
+}
+
+// normalizeSeverity bildet Schweregrade der Werkzeuge auf error, warning
+// und info ab.
+func normalizeSeverity(severity, fallback string) string {
+	switch strings.ToLower(severity) {
+	case "error", "fatal", "err", "e":
+		return "error"
+	case "warning", "warn", "w":
+		return "warning"
+	case "info", "information", "note", "message", "hint", "i":
+		return "info"
+	}
+	if fallback == "" {
+		return "error"
+	}
+	return fallback
+}
@@ -15,16 +15,38 @@ import (
 )
 
 // ProjectConfig beschreibt die .leoedit.json Datei im Projektstamm.
+// Include/Exclude sind Globs in gitignore-Syntax (relativ zum Projektstamm),
+// die zusätzlich zu den .gitignore-Dateien gelten (siehe ignore.go).
 type ProjectConfig struct {
-	Name       string `json:"name"`
-	RootPath   string `json:"rootPath"`
-	Version    string `json:"version"`
-	Created    string `json:"created"`
-	LastOpened string `json:"lastOpened"`
+	Name       string        `json:"name"`
+	RootPath   string        `json:"rootPath"`
+	Version    string        `json:"version"`
+	Created    string        `json:"created"`
+	LastOpened string        `json:"lastOpened"`
+	Include    []string      `json:"include,omitempty"` // Nur passende Dateien anzeigen/durchsuchen
+	Exclude    []string      `json:"exclude,omitempty"` // Passende Dateien/Ordner ausblenden
+	Hide       []string      `json:"hide,omitempty"`    // Nur im Explorer ausblenden (Suche unverändert)
+	Show       []string      `json:"show,omitempty"`    // Im Explorer immer anzeigen, z.B. ".env" oder ".github/"
+	Tasks      []ProjectTask `json:"tasks,omitempty"`   // Build-/Run-Befehle (siehe taskRunner.go)
+
+	ProblemMatchers []ProblemMatcherConfig `json:"problemMatchers,omitempty"` // Eigene Matcher (siehe problemMatcher.go)
+
+	// Editor-Einstellungen für das Projekt und pro Sprache (siehe editorSettings.go)
+	Editor    *EditorOverrides           `json:"editor,omitempty"`
+	Languages map[string]EditorOverrides `json:"languages,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Unbekannte Schlüssel (siehe projectSchema.go)
+}
+
+// OpenProjectResult ist das Ergebnis von OpenProject. Warnings enthält
+// ungültige Werte der .leoedit.json (siehe validateProjectConfig).
+type OpenProjectResult struct {
+	Config   *ProjectConfig       `json:"config"`
+	Warnings []ProjectConfigIssue `json:"warnings"`
 }
 
 const projectConfigFile = ".leoedit.json"
-const projectConfigVersion = "1.0"
+const projectConfigVersion = "1.2" // Schema-Version, siehe projectSchema.go
 
 // GetRecentProjects gibt die Liste der kürzlich geöffneten Projekte zurück.
 func (a *App) GetRecentProjects() []RecentProject {
@@ -108,12 +130,17 @@ func (a *App) CreateProject(folderPath, projectName string) (*ProjectConfig, err
 	}
 
 	a.AddRecentProject(config.Name, config.RootPath)
+	setOpenProjectRoot(config.RootPath)
+	startSearchIndex(config.RootPath)
+	a.WatchProject(config.RootPath) // Ohne Überwachung (z.B. nicht Linux) einfach weiter
 
 	return config, nil
 }
 
 // OpenProject öffnet ein bestehendes Projekt und aktualisiert lastOpened.
-func (a *App) OpenProject(folderPath string) (*ProjectConfig, error) {
+// Nur Syntax- und Typfehler verhindern das Öffnen; ungültige Werte (z.B.
+// ein Task ohne Befehl) werden in Warnings gemeldet.
+func (a *App) OpenProject(folderPath string) (*OpenProjectResult, error) {
 	// Pfad normalisieren
 	folderPath, err := filepath.Abs(folderPath)
 	if err != nil {
@@ -122,15 +149,19 @@ func (a *App) OpenProject(folderPath string) (*ProjectConfig, error) {
 
 	configPath := filepath.Join(folderPath, projectConfigFile)
 
-	// Konfiguration lesen
+	// Konfiguration lesen und prüfen
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("Projektdatei nicht gefunden: %w", err)
 	}
-
-	var config ProjectConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("Projektdatei ungültig: %w", err)
+	loaded, err := parseProjectConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	config := *loaded
+	warnings := locateConfigIssues(data, validateProjectConfig(loaded))
+	if warnings == nil {
+		warnings = []ProjectConfigIssue{}
 	}
 
 	// RootPath aktualisieren (falls Ordner verschoben wurde)
@@ -145,8 +176,18 @@ func (a *App) OpenProject(folderPath string) (*ProjectConfig, error) {
 	}
 
 	a.AddRecentProject(config.Name, config.RootPath)
+	setOpenProjectRoot(config.RootPath)
+	startSearchIndex(config.RootPath)
+	a.WatchProject(config.RootPath) // Ohne Überwachung (z.B. nicht Linux) einfach weiter
+
+	return &OpenProjectResult{Config: &config, Warnings: warnings}, nil
+}
 
-	return &config, nil
+// CloseProject schließt das geöffnete Projekt: Die Überwachung endet und
+// Dateioperationen sind nicht mehr auf das Projekt beschränkt.
+func (a *App) CloseProject() {
+	setOpenProjectRoot("")
+	a.UnwatchProject()
 }
 
 // SelectProjectFolder öffnet einen nativen Ordner-Auswahl-Dialog.
@@ -170,6 +211,12 @@ func (a *App) CheckProjectExists(folderPath string) bool {
 // ListProjectDirectory listet ein Verzeichnis innerhalb eines Projekts.
 // Verhindert Navigation außerhalb des Projektstamms.
 func (a *App) ListProjectDirectory(path, projectRoot string) DirectoryResult {
+	return a.ListProjectDirectoryWithOptions(path, projectRoot, ListOptions{})
+}
+
+// ListProjectDirectoryWithOptions listet wie ListProjectDirectory und
+// sortiert nach options (siehe ListDirectoryWithOptions).
+func (a *App) ListProjectDirectoryWithOptions(path, projectRoot string, options ListOptions) DirectoryResult {
 	// Pfade normalisieren
 	path, err := filepath.Abs(path)
 	if err != nil {
@@ -181,13 +228,14 @@ func (a *App) ListProjectDirectory(path, projectRoot string) DirectoryResult {
 		return DirectoryResult{Error: "Ungültiger Projektstamm: " + err.Error()}
 	}
 
-	// Sicherheitsprüfung: Pfad muss innerhalb des Projektstamms sein
-	if !isPathWithinRoot(path, projectRoot) {
-		return DirectoryResult{Error: "Zugriff außerhalb des Projekts nicht erlaubt"}
+	// Sicherheitsprüfung: Pfad muss innerhalb des Projektstamms sein,
+	// auch nach Auflösen von Symlinks (siehe projectPath.go)
+	if _, err := resolveProjectPath(path, projectRoot, true); err != nil {
+		return DirectoryResult{Error: err.Error()}
 	}
 
-	// Normales Verzeichnis-Listing verwenden
-	result := a.ListDirectory(path)
+	// Verzeichnis-Listing mit .gitignore und include/exclude des Projekts
+	result := a.listDirectory(path, options, projectRoot)
 
 	// Parent anpassen: Leer wenn wir am Projektstamm sind
 	if path == projectRoot {
@@ -200,9 +248,21 @@ func (a *App) ListProjectDirectory(path, projectRoot string) DirectoryResult {
 	return result
 }
 
+// readProjectConfig liest die .leoedit.json aus einem Projektordner.
+// Der Inhalt wird nicht geprüft (siehe ValidateProjectConfig).
+func readProjectConfig(folderPath string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(filepath.Join(folderPath, projectConfigFile))
+	if err != nil {
+		return nil, fmt.Errorf("Projektdatei nicht gefunden: %w", err)
+	}
+
+	// Ältere Versionen migrieren, unbekannte Schlüssel erhalten
+	return parseProjectConfig(data)
+}
+
 // saveProjectConfig speichert die Projektkonfiguration.
 func (a *App) saveProjectConfig(configPath string, config *ProjectConfig) error {
-	data, err := json.MarshalIndent(config, "", "  ")
+	data, err := marshalProjectJSON(config, "  ")
 	if err != nil {
 		return fmt.Errorf("Fehler beim Serialisieren: %w", err)
 	}
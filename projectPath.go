@@ -0,0 +1,196 @@
+// projectPath.go — Symlink-sichere Pfadauflösung innerhalb eines Projekts.
+// isPathWithinRoot vergleicht nur Zeichenketten; ein Symlink im Projekt,
+// der auf /etc zeigt, würde die Prüfung bestehen. resolveProjectPath löst
+// deshalb Symlinks auf (auch für noch nicht existierende Pfade und defekte
+// Links) und lehnt Ziele außerhalb des Projekts ab.
+//
+// Solange ein Projekt geöffnet ist, prüfen Lesen, Speichern, Umbenennen und
+// Löschen jeden Pfad im Projekt damit (guardProjectPath).
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Maximale Anzahl aufgelöster Symlinks (wie MAXSYMLINKS unter Linux)
+const maxSymlinkHops = 40
+
+// caseInsensitiveFS ist true auf Systemen, deren Dateisystem Groß- und
+// Kleinschreibung üblicherweise nicht unterscheidet.
+var caseInsensitiveFS = runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+
+var (
+	errOutsideProject = errors.New("Zugriff außerhalb des Projekts nicht erlaubt")
+	errSymlinkLoop    = errors.New("Symlink-Schleife")
+)
+
+// Stammordner des geöffneten Projekts (leer: kein Projekt geöffnet)
+var (
+	openProjectRoot   string
+	openProjectRootMu sync.RWMutex
+)
+
+func setOpenProjectRoot(root string) {
+	openProjectRootMu.Lock()
+	defer openProjectRootMu.Unlock()
+	openProjectRoot = root
+}
+
+func currentProjectRoot() string {
+	openProjectRootMu.RLock()
+	defer openProjectRootMu.RUnlock()
+	return openProjectRoot
+}
+
+// guardProjectPath prüft Pfade der allgemeinen Datei-Funktionen: Liegt path
+// im geöffneten Projekt, muss er auch nach Auflösen von Symlinks dort
+// bleiben (siehe resolveProjectPath). Pfade außerhalb des Projekts (Datei-
+// Explorer, Öffnen-Dialog) werden unverändert zurückgegeben.
+func guardProjectPath(path string, followLast bool) (string, error) {
+	root := currentProjectRoot()
+	if root == "" || path == "" {
+		return path, nil
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("Ungültiger Pfad: %w", err)
+	}
+	if !pathWithinRoot(absPath, root, caseInsensitiveFS) {
+		return path, nil
+	}
+	return resolveProjectPath(absPath, root, followLast)
+}
+
+// resolveProjectPath normalisiert path und prüft, dass er nach Auflösen
+// aller Symlinks innerhalb von projectRoot liegt. Ist followLast false,
+// wird der letzte Bestandteil nicht aufgelöst (Umbenennen/Löschen eines
+// Symlinks betrifft nur den Link). Zurückgegeben wird der normalisierte,
+// nicht aufgelöste Pfad.
+func resolveProjectPath(path, projectRoot string, followLast bool) (string, error) {
+	return resolveProjectPathFold(path, projectRoot, followLast, caseInsensitiveFS)
+}
+
+// resolveProjectPathFold ist resolveProjectPath mit wählbarem Vergleich
+// von Groß- und Kleinschreibung.
+func resolveProjectPathFold(path, projectRoot string, followLast, foldCase bool) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("Pfad darf nicht leer sein")
+	}
+	if projectRoot == "" {
+		return "", fmt.Errorf("Projektstamm darf nicht leer sein")
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("Ungültiger Pfad: %w", err)
+	}
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return "", fmt.Errorf("Ungültiger Projektstamm: %w", err)
+	}
+	realRoot, err := evalSymlinksPartial(root)
+	if err != nil {
+		return "", fmt.Errorf("Ungültiger Projektstamm: %w", err)
+	}
+
+	var resolved string
+	if followLast {
+		resolved, err = evalSymlinksPartial(abs)
+	} else {
+		resolved, err = evalSymlinksPartial(filepath.Dir(abs))
+		resolved = filepath.Join(resolved, filepath.Base(abs))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Der Pfad darf über den angegebenen oder den aufgelösten Stamm
+	// angesprochen werden, muss aber aufgelöst im Projekt bleiben
+	if !pathWithinRoot(resolved, realRoot, foldCase) {
+		return "", errOutsideProject
+	}
+	return abs, nil
+}
+
+// evalSymlinksPartial löst alle Symlinks in path auf. Anders als
+// filepath.EvalSymlinks darf das Ende des Pfades fehlen (z.B. beim
+// Speichern einer neuen Datei); defekte Symlinks werden bis zu ihrem
+// Ziel verfolgt, da Schreiben dort eine Datei anlegen würde.
+func evalSymlinksPartial(path string) (string, error) {
+	path = filepath.Clean(path)
+	for hops := 0; hops <= maxSymlinkHops; hops++ {
+		// Längsten existierenden Anfang suchen
+		existing, rest := path, ""
+		for {
+			if _, err := os.Lstat(existing); err == nil {
+				break
+			}
+			parent := filepath.Dir(existing)
+			if parent == existing {
+				return path, nil
+			}
+			rest = filepath.Join(filepath.Base(existing), rest)
+			existing = parent
+		}
+
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		var pathErr *fs.PathError
+		if !os.IsNotExist(err) {
+			if errors.As(err, &pathErr) {
+				return "", fmt.Errorf("Pfad kann nicht aufgelöst werden: %w", err)
+			}
+			return "", errSymlinkLoop // EvalSymlinks: too many links
+		}
+
+		// existing ist ein defekter Symlink: eine Ebene von Hand auflösen
+		// (relativ zum aufgelösten Ordner des Links) und erneut versuchen
+		target, err := os.Readlink(existing)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(target) {
+			dir, err := filepath.EvalSymlinks(filepath.Dir(existing))
+			if err != nil {
+				return "", fmt.Errorf("Pfad kann nicht aufgelöst werden: %w", err)
+			}
+			target = filepath.Join(dir, target)
+		}
+		path = filepath.Join(target, rest)
+	}
+	return "", errSymlinkLoop
+}
+
+// pathWithinRoot ist isPathWithinRoot mit optionalem Vergleich ohne
+// Berücksichtigung von Groß- und Kleinschreibung.
+func pathWithinRoot(path, root string, foldCase bool) bool {
+	if foldCase {
+		path, root = strings.ToLower(path), strings.ToLower(root)
+	}
+	return isPathWithinRoot(path, root)
+}
+
+// samePath vergleicht zwei normalisierte Pfade.
+func samePath(a, b string, foldCase bool) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if foldCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// isExternalSymlink prüft, ob der Symlink path auf ein Ziel außerhalb von
+// root zeigt (oder nicht auflösbar ist).
+func isExternalSymlink(path, root string) bool {
+	_, err := resolveProjectPath(path, root, true)
+	return err != nil
+}
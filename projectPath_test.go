@@ -0,0 +1,212 @@
+// projectPath_test.go — Tests für die Symlink-sichere Pfadauflösung.
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newProjectFixture legt einen Projektordner und daneben einen Ordner
+// außerhalb des Projekts mit je einer Datei an.
+func newProjectFixture(t *testing.T) (root, outside string) {
+	t.Helper()
+	base := t.TempDir()
+	root = filepath.Join(base, "Project")
+	outside = filepath.Join(base, "outside")
+	for _, dir := range []string{root, outside} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile(t, filepath.Join(root, "inside.txt"))
+	writeTestFile(t, filepath.Join(outside, "secret.txt"))
+	return root, outside
+}
+
+func writeTestFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// symlinkOrSkip legt einen Symlink an; ohne Berechtigung dafür (z.B. unter
+// Windows) wird der Test übersprungen.
+func symlinkOrSkip(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("Symlinks nicht verfügbar: %v", err)
+	}
+}
+
+func TestResolveProjectPathInside(t *testing.T) {
+	root, _ := newProjectFixture(t)
+
+	for _, path := range []string{
+		filepath.Join(root, "inside.txt"),
+		filepath.Join(root, "new", "file.txt"), // noch nicht vorhanden
+		root,
+	} {
+		if _, err := resolveProjectPath(path, root, true); err != nil {
+			t.Errorf("resolveProjectPath(%q): %v", path, err)
+		}
+	}
+}
+
+func TestResolveProjectPathDotDotEscape(t *testing.T) {
+	root, _ := newProjectFixture(t)
+
+	path := root + string(filepath.Separator) + ".." + string(filepath.Separator) + filepath.Join("outside", "secret.txt")
+	if _, err := resolveProjectPath(path, root, true); !errors.Is(err, errOutsideProject) {
+		t.Fatalf("erwartet errOutsideProject, erhalten %v", err)
+	}
+}
+
+func TestResolveProjectPathSymlinkOutside(t *testing.T) {
+	root, outside := newProjectFixture(t)
+	symlinkOrSkip(t, outside, filepath.Join(root, "link"))
+
+	for _, path := range []string{
+		filepath.Join(root, "link"),
+		filepath.Join(root, "link", "secret.txt"),
+		filepath.Join(root, "link", "new.txt"),
+	} {
+		if _, err := resolveProjectPath(path, root, true); !errors.Is(err, errOutsideProject) {
+			t.Errorf("resolveProjectPath(%q): erwartet errOutsideProject, erhalten %v", path, err)
+		}
+	}
+	if !isExternalSymlink(filepath.Join(root, "link"), root) {
+		t.Error("isExternalSymlink: Link nach außen nicht erkannt")
+	}
+}
+
+func TestResolveProjectPathBrokenSymlinkOutside(t *testing.T) {
+	root, outside := newProjectFixture(t)
+	link := filepath.Join(root, "broken")
+	symlinkOrSkip(t, filepath.Join(outside, "missing.txt"), link)
+
+	// Schreiben über den Link würde außerhalb eine Datei anlegen
+	if _, err := resolveProjectPath(link, root, true); !errors.Is(err, errOutsideProject) {
+		t.Fatalf("erwartet errOutsideProject, erhalten %v", err)
+	}
+	// Den Link selbst darf man umbenennen oder löschen
+	if _, err := resolveProjectPath(link, root, false); err != nil {
+		t.Fatalf("followLast=false: %v", err)
+	}
+}
+
+func TestResolveProjectPathSymlinkLoop(t *testing.T) {
+	root, _ := newProjectFixture(t)
+	symlinkOrSkip(t, "b", filepath.Join(root, "a"))
+	symlinkOrSkip(t, "a", filepath.Join(root, "b"))
+
+	for _, path := range []string{
+		filepath.Join(root, "a"),
+		filepath.Join(root, "a", "file.txt"),
+	} {
+		if _, err := resolveProjectPath(path, root, true); !errors.Is(err, errSymlinkLoop) {
+			t.Errorf("resolveProjectPath(%q): erwartet errSymlinkLoop, erhalten %v", path, err)
+		}
+	}
+}
+
+func TestResolveProjectPathFoldCase(t *testing.T) {
+	root, _ := newProjectFixture(t)
+	path := filepath.Join(filepath.Dir(root), strings.ToUpper(filepath.Base(root)), "Inside.txt")
+
+	if _, err := resolveProjectPathFold(path, root, true, true); err != nil {
+		t.Fatalf("foldCase=true: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		// Dateisystem unterscheidet Groß- und Kleinschreibung
+		if _, err := resolveProjectPathFold(path, root, true, false); !errors.Is(err, errOutsideProject) {
+			t.Fatalf("foldCase=false: erwartet errOutsideProject, erhalten %v", err)
+		}
+	}
+}
+
+// openTestProject setzt root als geöffnetes Projekt, bis der Test endet.
+func openTestProject(t *testing.T, root string) {
+	t.Helper()
+	setOpenProjectRoot(root)
+	t.Cleanup(func() { setOpenProjectRoot("") })
+}
+
+func TestRenameFileMovesOnlyLink(t *testing.T) {
+	root, outside := newProjectFixture(t)
+	openTestProject(t, root)
+	link := filepath.Join(root, "link")
+	symlinkOrSkip(t, filepath.Join(outside, "secret.txt"), link)
+
+	a := &App{}
+	renamed := filepath.Join(root, "renamed")
+	if err := a.RenameFile(link, renamed); err != nil {
+		t.Fatalf("RenameFile: %v", err)
+	}
+	if info, err := os.Lstat(renamed); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("umbenannter Eintrag ist kein Symlink: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "secret.txt")); err != nil {
+		t.Fatalf("Ziel des Links wurde verändert: %v", err)
+	}
+
+	// Umbenennen nach außen wird abgelehnt
+	if err := a.RenameFile(renamed, filepath.Join(outside, "moved")); !errors.Is(err, errOutsideProject) {
+		t.Fatalf("erwartet errOutsideProject, erhalten %v", err)
+	}
+}
+
+func TestDeleteFileRemovesOnlyLink(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir()) // Papierkorb
+	root, outside := newProjectFixture(t)
+	openTestProject(t, root)
+	link := filepath.Join(root, "link")
+	symlinkOrSkip(t, outside, link)
+
+	a := &App{}
+	if err := a.DeleteFile(link); err != nil {
+		t.Fatalf("DeleteFile: %v", err)
+	}
+	if _, err := os.Lstat(link); !os.IsNotExist(err) {
+		t.Fatalf("Link existiert noch: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "secret.txt")); err != nil {
+		t.Fatalf("Ziel des Links wurde gelöscht: %v", err)
+	}
+}
+
+func TestDeleteFileRefusesProjectRoot(t *testing.T) {
+	root, _ := newProjectFixture(t)
+	openTestProject(t, root)
+
+	a := &App{}
+	for _, path := range []string{root, root + string(filepath.Separator)} {
+		if err := a.DeleteFilePermanently(path); err == nil {
+			t.Fatalf("DeleteFilePermanently(%q): Projektstamm wurde gelöscht", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "inside.txt")); err != nil {
+		t.Fatalf("Projekt wurde verändert: %v", err)
+	}
+}
+
+func TestSaveFileRefusesSymlinkOutside(t *testing.T) {
+	root, outside := newProjectFixture(t)
+	openTestProject(t, root)
+	link := filepath.Join(root, "link.txt")
+	symlinkOrSkip(t, filepath.Join(outside, "secret.txt"), link)
+
+	a := &App{}
+	if err := a.SaveFile("überschrieben\n", link); !errors.Is(err, errOutsideProject) {
+		t.Fatalf("erwartet errOutsideProject, erhalten %v", err)
+	}
+	if result := a.ReadTextFile(link); result.Error == "" {
+		t.Fatal("ReadTextFile: Datei außerhalb des Projekts gelesen")
+	}
+	if data, _ := os.ReadFile(filepath.Join(outside, "secret.txt")); string(data) != "test\n" {
+		t.Fatalf("Ziel des Links wurde verändert: %q", data)
+	}
+}
@@ -0,0 +1,640 @@
+// projectSchema.go — Versionierung, Migration und Prüfung der .leoedit.json.
+// Das Feld "version" gibt die Schema-Version an. Ältere Dateien werden beim
+// Lesen schrittweise migriert (siehe projectConfigMigrations) und beim
+// nächsten Speichern mit der aktuellen Version geschrieben. Dateien einer
+// neueren Leoedit-Version werden nicht zurückgestuft.
+//
+// Unbekannte Schlüssel (z.B. von neueren Versionen) bleiben in Extra
+// erhalten und werden unverändert zurückgeschrieben, auch in Tasks,
+// Problem-Matchern und Editor-Einstellungen.
+//
+// Schema-Versionen:
+//   1.0 → name, rootPath, version, created, lastOpened
+//   1.1 → include, exclude, hide, show (Listen), tasks, problemMatchers
+//   1.2 → editor, languages (siehe editorSettings.go)
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.ValidateProjectConfig(folderPath) → []ProjectConfigIssue
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProjectConfigIssue beschreibt einen Fehler in der .leoedit.json.
+// Field ist der Pfad zum Feld, z.B. "tasks[1].command" (leer = ganze
+// Datei). Line und Column beginnen bei 1 (0 = unbekannt).
+type ProjectConfigIssue struct {
+	Field   string `json:"field"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// projectConfigError fasst die Fehler einer .leoedit.json zusammen.
+type projectConfigError struct {
+	issues []ProjectConfigIssue
+}
+
+func (e *projectConfigError) Error() string {
+	parts := make([]string, 0, len(e.issues))
+	for _, issue := range e.issues {
+		part := issue.Message
+		if issue.Field != "" {
+			part = issue.Field + ": " + part
+		}
+		if issue.Line > 0 {
+			part += fmt.Sprintf(" (Zeile %d, Spalte %d)", issue.Line, issue.Column)
+		}
+		parts = append(parts, part)
+	}
+	return "Projektdatei ungültig: " + strings.Join(parts, "; ")
+}
+
+// projectConfigMigration hebt die rohen Daten von Version from auf to.
+type projectConfigMigration struct {
+	from    string
+	to      string
+	migrate func(raw map[string]any) error
+}
+
+// projectConfigMigrations sind die Migrationsschritte in aufsteigender
+// Reihenfolge; der letzte endet bei projectConfigVersion.
+var projectConfigMigrations = []projectConfigMigration{
+	{from: "1.0", to: "1.1", migrate: migrateProjectConfig10},
+	{from: "1.1", to: "1.2"}, // Nur neue Felder
+}
+
+// ValidateProjectConfig prüft die .leoedit.json eines Projekts und liefert
+// alle gefundenen Fehler mit Position (leer = gültig), z.B. um sie beim
+// Bearbeiten der Datei im Editor anzuzeigen.
+func (a *App) ValidateProjectConfig(folderPath string) []ProjectConfigIssue {
+	data, err := os.ReadFile(filepath.Join(folderPath, projectConfigFile))
+	if err != nil {
+		return []ProjectConfigIssue{{Message: "Projektdatei nicht gefunden: " + err.Error()}}
+	}
+
+	config, err := parseProjectConfig(data)
+	if err != nil {
+		var configErr *projectConfigError
+		if errors.As(err, &configErr) {
+			return configErr.issues
+		}
+		return []ProjectConfigIssue{{Message: err.Error()}}
+	}
+
+	return locateConfigIssues(data, validateProjectConfig(config))
+}
+
+// locateConfigIssues ergänzt fehlende Positionen anhand der Dateidaten.
+func locateConfigIssues(data []byte, issues []ProjectConfigIssue) []ProjectConfigIssue {
+	for i := range issues {
+		if issues[i].Line == 0 {
+			issues[i].Line, issues[i].Column = jsonFieldPosition(data, issues[i].Field)
+		}
+	}
+	return issues
+}
+
+// parseProjectConfig liest den Inhalt einer .leoedit.json und migriert
+// ältere Versionen. Syntax- und Typfehler werden als projectConfigError
+// mit Position gemeldet.
+func parseProjectConfig(data []byte) (*ProjectConfig, error) {
+	var raw map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // Zahlen unbekannter Felder nicht verfälschen
+	if err := dec.Decode(&raw); err != nil {
+		return nil, jsonDecodeError(data, err)
+	}
+	if raw == nil {
+		return nil, &projectConfigError{[]ProjectConfigIssue{{Message: "Objekt erwartet"}}}
+	}
+
+	migrated, err := migrateProjectConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	if migrated {
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, fmt.Errorf("Fehler beim Migrieren: %w", err)
+		}
+	}
+
+	var config ProjectConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		issue := jsonDecodeError(data, err)
+		if migrated {
+			// Positionen beziehen sich auf die migrierten Daten
+			for i := range issue.issues {
+				issue.issues[i].Line, issue.issues[i].Column = 0, 0
+			}
+		}
+		return nil, issue
+	}
+	if err := extractExtraFields(&config, raw); err != nil {
+		return nil, fmt.Errorf("Projektdatei ungültig: %w", err)
+	}
+	return &config, nil
+}
+
+// migrateProjectConfig wendet alle nötigen Migrationen an und gibt true
+// zurück, wenn die Daten geändert wurden. Fehlt "version", gilt "1.0".
+func migrateProjectConfig(raw map[string]any) (bool, error) {
+	version := "1.0"
+	if value, ok := raw["version"]; ok {
+		s, isString := value.(string)
+		if !isString {
+			return false, &projectConfigError{[]ProjectConfigIssue{{Field: "version", Message: "Zeichenkette erwartet"}}}
+		}
+		version = s
+	}
+	if _, ok := parseConfigVersion(version); !ok {
+		return false, &projectConfigError{[]ProjectConfigIssue{{Field: "version", Message: fmt.Sprintf("Ungültige Version %q", version)}}}
+	}
+
+	migrated := false
+	for _, m := range projectConfigMigrations {
+		if compareConfigVersions(version, m.to) >= 0 {
+			continue
+		}
+		if m.migrate == nil {
+			// Keine Umwandlung nötig
+		} else if err := m.migrate(raw); err != nil {
+			return false, fmt.Errorf("Migration von Version %s auf %s fehlgeschlagen: %w", m.from, m.to, err)
+		}
+		version = m.to
+		raw["version"] = version
+		migrated = true
+	}
+	return migrated, nil
+}
+
+// migrateProjectConfig10 (1.0 → 1.1): Muster-Listen durften von Hand als
+// einzelne Zeichenkette eingetragen sein und sind jetzt immer Listen.
+func migrateProjectConfig10(raw map[string]any) error {
+	for _, key := range []string{"include", "exclude", "hide", "show"} {
+		if s, ok := raw[key].(string); ok {
+			if s == "" {
+				delete(raw, key)
+			} else {
+				raw[key] = []any{s}
+			}
+		}
+	}
+	return nil
+}
+
+// parseConfigVersion zerlegt eine Version wie "1.1" in ihre Zahlen.
+func parseConfigVersion(version string) ([]int, bool) {
+	parts := strings.Split(version, ".")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		numbers[i] = n
+	}
+	return numbers, true
+}
+
+// compareConfigVersions vergleicht zwei gültige Versionen (-1, 0, 1).
+func compareConfigVersions(a, b string) int {
+	va, _ := parseConfigVersion(a)
+	vb, _ := parseConfigVersion(b)
+	for i := 0; i < max(len(va), len(vb)); i++ {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// validateProjectConfig prüft die Inhalte einer gelesenen Konfiguration.
+func validateProjectConfig(config *ProjectConfig) []ProjectConfigIssue {
+	issues := []ProjectConfigIssue{}
+	add := func(field, format string, args ...any) {
+		issues = append(issues, ProjectConfigIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if strings.TrimSpace(config.Name) == "" {
+		add("name", "Name fehlt")
+	}
+	for key, patterns := range map[string][]string{
+		"include": config.Include, "exclude": config.Exclude,
+		"hide": config.Hide, "show": config.Show,
+	} {
+		for i, pattern := range patterns {
+			if strings.TrimSpace(pattern) == "" {
+				add(fmt.Sprintf("%s[%d]", key, i), "Leeres Muster")
+			}
+		}
+	}
+
+	matchers := make(map[string]bool)
+	for name := range builtinProblemMatchers {
+		matchers[name] = true
+	}
+	custom := make(map[string]bool)
+	for i, m := range config.ProblemMatchers {
+		field := fmt.Sprintf("problemMatchers[%d]", i)
+		switch {
+		case m.Name == "":
+			add(field+".name", "Name fehlt")
+		case custom[m.Name]:
+			add(field+".name", "Problem-Matcher %s ist doppelt definiert", m.Name)
+		}
+		custom[m.Name] = true
+		matchers[m.Name] = true
+
+		if m.Pattern == "" && len(m.Patterns) == 0 {
+			add(field+".pattern", "Pattern fehlt")
+		}
+		if m.Pattern != "" {
+			if msg := checkProblemPattern(m.Pattern); msg != "" {
+				add(field+".pattern", "%s", msg)
+			}
+		}
+		for j, pattern := range m.Patterns {
+			if msg := checkProblemPattern(pattern); msg != "" {
+				add(fmt.Sprintf("%s.patterns[%d]", field, j), "%s", msg)
+			}
+		}
+		if m.ResetPattern != "" {
+			if _, err := regexp.Compile(m.ResetPattern); err != nil {
+				add(field+".resetPattern", "Ungültiger Regex: %v", err)
+			}
+		}
+		if m.Severity != "" && normalizeSeverity(m.Severity, "?") == "?" {
+			add(field+".severity", "Unbekannter Schweregrad %q (error, warning oder info)", m.Severity)
+		}
+	}
+
+	if config.Editor != nil {
+		issues = append(issues, editorOverrideIssues("editor", *config.Editor)...)
+	}
+	for language, overrides := range config.Languages {
+		if strings.TrimSpace(language) == "" {
+			add("languages", "Leerer Sprach-Schlüssel")
+		}
+		issues = append(issues, editorOverrideIssues("languages."+language, overrides)...)
+	}
+
+	tasks := make(map[string]bool)
+	for i, task := range config.Tasks {
+		field := fmt.Sprintf("tasks[%d]", i)
+		switch {
+		case strings.TrimSpace(task.Name) == "":
+			add(field+".name", "Name fehlt")
+		case tasks[task.Name]:
+			add(field+".name", "Task %s ist doppelt definiert", task.Name)
+		}
+		tasks[task.Name] = true
+
+		if strings.TrimSpace(task.Command) == "" {
+			add(field+".command", "Befehl fehlt")
+		}
+		if task.Cwd != "" && !filepath.IsAbs(task.Cwd) {
+			if rel := filepath.Clean(task.Cwd); rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				add(field+".cwd", "Arbeitsverzeichnis muss im Projekt liegen")
+			}
+		}
+		for key := range task.Env {
+			if key == "" || strings.Contains(key, "=") {
+				add(field+".env", "Ungültiger Variablenname %q", key)
+			}
+		}
+		if task.ProblemMatcher != "" && !matchers[task.ProblemMatcher] {
+			add(field+".problemMatcher", "Problem-Matcher nicht gefunden: %s", task.ProblemMatcher)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Field < issues[j].Field })
+	return issues
+}
+
+// checkProblemPattern prüft ein Pattern eines eigenen Problem-Matchers
+// und gibt eine Fehlermeldung zurück (leer = gültig).
+func checkProblemPattern(pattern string) string {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "Ungültiger Regex: " + err.Error()
+	}
+	if re.SubexpIndex("file") < 0 && re.SubexpIndex("message") < 0 {
+		return "Pattern braucht eine Gruppe (?P<file>...) oder (?P<message>...)"
+	}
+	return ""
+}
+
+// jsonDecodeError wandelt Fehler von encoding/json in einen
+// projectConfigError mit Zeile und Spalte um.
+func jsonDecodeError(data []byte, err error) *projectConfigError {
+	issue := ProjectConfigIssue{Message: err.Error()}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		issue.Message = "Syntaxfehler: " + syntaxErr.Error()
+		issue.Line, issue.Column = offsetPosition(data, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		issue.Field = jsonArrayIndex.ReplaceAllString(typeErr.Field, "[$1]")
+		issue.Message = fmt.Sprintf("Falscher Typ: %s statt %s", typeErr.Value, typeErr.Type)
+		issue.Line, issue.Column = offsetPosition(data, typeErr.Offset)
+	case errors.Is(err, io.EOF):
+		issue.Message = "Datei ist leer oder unvollständig"
+	}
+	return &projectConfigError{[]ProjectConfigIssue{issue}}
+}
+
+// offsetPosition rechnet einen Byte-Offset in Zeile und Spalte um.
+func offsetPosition(data []byte, offset int64) (line, column int) {
+	if offset < 0 || offset > int64(len(data)) {
+		return 0, 0
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// jsonArrayIndex findet Indizes in Feldpfaden von encoding/json ("tasks.0.cwd")
+var jsonArrayIndex = regexp.MustCompile(`\.(\d+)`)
+
+// jsonPathSegment zerlegt Feldpfade wie "tasks[1].command"
+var jsonPathSegment = regexp.MustCompile(`[^.\[\]]+|\[\d+\]`)
+
+// jsonFieldPosition sucht die Position eines Feldes (z.B. "tasks[1].cwd")
+// in den JSON-Daten. Nicht gefundene Felder ergeben 0, 0.
+func jsonFieldPosition(data []byte, field string) (line, column int) {
+	if field == "" {
+		return 0, 0
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	offset := findJSONPath(dec, jsonPathSegment.FindAllString(field, -1))
+	if offset < 0 {
+		return 0, 0
+	}
+
+	// InputOffset steht hinter dem vorigen Token; Trenner überspringen
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	return offsetPosition(data, offset)
+}
+
+// findJSONPath liest den nächsten Wert aus dec und folgt path hinein.
+// Gibt den Offset vor dem gesuchten Schlüssel bzw. Element zurück, -1
+// wenn der Pfad nicht existiert.
+func findJSONPath(dec *json.Decoder, path []string) int64 {
+	token, err := dec.Token()
+	if err != nil {
+		return -1
+	}
+
+	switch token {
+	case json.Delim('{'):
+		for dec.More() {
+			start := dec.InputOffset()
+			key, err := dec.Token()
+			if err != nil {
+				return -1
+			}
+			if key == path[0] {
+				if len(path) == 1 {
+					return start
+				}
+				return findJSONPath(dec, path[1:])
+			}
+			if skipJSONValue(dec) != nil {
+				return -1
+			}
+		}
+	case json.Delim('['):
+		index, err := strconv.Atoi(strings.Trim(path[0], "[]"))
+		if err != nil {
+			return -1
+		}
+		for i := 0; dec.More(); i++ {
+			if i == index {
+				start := dec.InputOffset()
+				if len(path) == 1 {
+					return start
+				}
+				return findJSONPath(dec, path[1:])
+			}
+			if skipJSONValue(dec) != nil {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// skipJSONValue überliest den nächsten Wert samt Inhalt.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// extractExtraFields überträgt die unbekannten Schlüssel aus den rohen
+// Daten in die Extra-Felder der Konfiguration, ihrer Editor-Einstellungen,
+// Tasks und Matcher.
+// (Eigene UnmarshalJSON-Methoden würden die Fehlerposition verlieren.)
+func extractExtraFields(config *ProjectConfig, raw map[string]any) error {
+	var err error
+	if config.Extra, err = unknownFields(raw, reflect.TypeOf(*config)); err != nil {
+		return err
+	}
+
+	tasks, _ := rawField(raw, "tasks").([]any)
+	for i := 0; i < min(len(tasks), len(config.Tasks)); i++ {
+		if fields, ok := tasks[i].(map[string]any); ok {
+			if config.Tasks[i].Extra, err = unknownFields(fields, reflect.TypeOf(config.Tasks[i])); err != nil {
+				return err
+			}
+		}
+	}
+	if fields, ok := rawField(raw, "editor").(map[string]any); ok && config.Editor != nil {
+		if config.Editor.Extra, err = unknownFields(fields, reflect.TypeOf(*config.Editor)); err != nil {
+			return err
+		}
+	}
+	languages, _ := rawField(raw, "languages").(map[string]any)
+	for language, overrides := range config.Languages {
+		if fields, ok := languages[language].(map[string]any); ok {
+			if overrides.Extra, err = unknownFields(fields, reflect.TypeOf(overrides)); err != nil {
+				return err
+			}
+			config.Languages[language] = overrides
+		}
+	}
+	matchers, _ := rawField(raw, "problemMatchers").([]any)
+	for i := 0; i < min(len(matchers), len(config.ProblemMatchers)); i++ {
+		if fields, ok := matchers[i].(map[string]any); ok {
+			if config.ProblemMatchers[i].Extra, err = unknownFields(fields, reflect.TypeOf(config.ProblemMatchers[i])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rawField sucht einen Schlüssel wie encoding/json, notfalls ohne
+// Beachtung der Groß- und Kleinschreibung.
+func rawField(raw map[string]any, name string) any {
+	if value, ok := raw[name]; ok {
+		return value
+	}
+	for key, value := range raw {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return nil
+}
+
+// unknownFields liefert die Schlüssel aus fields, die keinem Feld des
+// Struct-Typs t entsprechen (nil, wenn es keine gibt).
+func unknownFields(fields map[string]any, t reflect.Type) (map[string]json.RawMessage, error) {
+	known := jsonFieldNames(t)
+	var extra map[string]json.RawMessage
+	for key, value := range fields {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+		data, err := marshalProjectJSON(value, "")
+		if err != nil {
+			return nil, err
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[key] = data
+	}
+	return extra, nil
+}
+
+// Beim Schreiben werden die Extra-Felder über einen Alias-Typ (ohne
+// eigene Methoden) wieder angehängt.
+
+func (c ProjectConfig) MarshalJSON() ([]byte, error) {
+	type plain ProjectConfig
+	return marshalWithExtra(plain(c), c.Extra)
+}
+
+func (t ProjectTask) MarshalJSON() ([]byte, error) {
+	type plain ProjectTask
+	return marshalWithExtra(plain(t), t.Extra)
+}
+
+func (m ProblemMatcherConfig) MarshalJSON() ([]byte, error) {
+	type plain ProblemMatcherConfig
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+func (o EditorOverrides) MarshalJSON() ([]byte, error) {
+	type plain EditorOverrides
+	return marshalWithExtra(plain(o), o.Extra)
+}
+
+// marshalWithExtra serialisiert v und hängt die unbekannten Schlüssel
+// (sortiert) an, sofern v sie nicht selbst enthält.
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := marshalProjectJSON(v, "")
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(v))
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		if !known[strings.ToLower(key)] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1]) // ohne schließende Klammer
+	for i, key := range keys {
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(extra[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalProjectJSON serialisiert wie json.MarshalIndent, aber ohne
+// "<", ">" und "&" zu maskieren, damit Regex-Gruppen wie (?P<file>...)
+// in der .leoedit.json lesbar bleiben.
+func marshalProjectJSON(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// jsonFieldNames liefert die JSON-Namen der Felder eines Struct-Typs in
+// Kleinschreibung (encoding/json ordnet Schlüssel ohne Beachtung der
+// Groß- und Kleinschreibung zu).
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
@@ -0,0 +1,380 @@
+// recovery.go — Wiederherstellung ungespeicherter Tabs nach einem Absturz.
+// Das Frontend meldet den Inhalt geänderter Tabs laufend an SnapshotBuffer.
+// Die Snapshots werden gedrosselt (höchstens alle recoverySnapshotInterval)
+// unter <Konfigurationsordner>/Leoedit/recovery/<Sitzung>/ gespeichert.
+// Beim normalen Beenden wird der Sitzungsordner gelöscht; bleibt er nach
+// einem Absturz liegen, wird er beim nächsten Start angeboten:
+//   recovery_available → []RecoverySession (in domReady, falls vorhanden)
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.SnapshotBuffer(snapshot)          → Tab-Inhalt sichern
+//   window.go.main.App.ClearBufferSnapshot(tabId, path)  → Tab gespeichert/geschlossen
+//   window.go.main.App.GetRecoverableSessions()
+//   window.go.main.App.RestoreSession(sessionId)         → Inhalte, Sitzung bleibt erhalten
+//   window.go.main.App.DiscardSession(sessionId)         → nach Verwerfen bzw. erneutem Sichern
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	recoverySnapshotInterval = 2 * time.Second
+	recoveryMaxAge           = 14 * 24 * time.Hour // Ältere Sitzungen werden gelöscht
+	recoverySessionFile      = "session.json"
+)
+
+// BufferSnapshot ist der Inhalt eines ungespeicherten Tabs.
+// Path ist bei neuen, noch nie gespeicherten Tabs leer.
+// Fingerprint ist der Stand der Datei beim Laden (siehe fileConflict.go).
+type BufferSnapshot struct {
+	TabID       string          `json:"tabId"`
+	Path        string          `json:"path"`
+	Title       string          `json:"title"`
+	Content     string          `json:"content"`
+	Fingerprint FileFingerprint `json:"fingerprint"`
+	SavedAt     string          `json:"savedAt"` // RFC3339, wird vom Backend gesetzt
+}
+
+// RecoveredBuffer beschreibt einen wiederherstellbaren Tab.
+// Content ist nur bei RestoreSession gefüllt. DiskChanged ist true, wenn
+// die Datei seit dem Laden auf der Festplatte geändert wurde.
+type RecoveredBuffer struct {
+	BufferSnapshot
+	Size        int  `json:"size"`
+	DiskChanged bool `json:"diskChanged"`
+}
+
+// RecoverySession ist eine abgestürzte Sitzung mit ungespeicherten Tabs.
+type RecoverySession struct {
+	SessionID string            `json:"sessionId"`
+	StartedAt string            `json:"startedAt"`
+	UpdatedAt string            `json:"updatedAt"`
+	Buffers   []RecoveredBuffer `json:"buffers"`
+}
+
+// recoverySessionInfo wird als session.json in jedem Sitzungsordner abgelegt.
+type recoverySessionInfo struct {
+	PID       int    `json:"pid"`
+	StartedAt string `json:"startedAt"`
+}
+
+// recoveryStore verwaltet die Snapshots der laufenden Sitzung.
+type recoveryStore struct {
+	root string // recovery-Ordner
+	dir  string // Ordner der laufenden Sitzung
+
+	mu      sync.Mutex
+	pending map[string]BufferSnapshot // Dateiname -> noch nicht geschriebener Snapshot
+	timer   *time.Timer
+}
+
+// recovery ist der Store der laufenden Sitzung (nil, falls nicht verfügbar)
+var recovery *recoveryStore
+var recoveryMu sync.Mutex
+
+// startRecovery legt den Sitzungsordner an und räumt alte Sitzungen auf.
+// Wird beim Start der App aufgerufen.
+func startRecovery() {
+	root, err := getAppDataDir("recovery")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Recovery nicht verfügbar: %v\n", err)
+		return
+	}
+	os.Chmod(root, 0700) // Snapshots können vertrauliche Inhalte enthalten
+
+	now := time.Now()
+	sessionID := fmt.Sprintf("%s-%d", now.Format("20060102-150405"), os.Getpid())
+	dir := filepath.Join(root, sessionID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Recovery nicht verfügbar: %v\n", err)
+		return
+	}
+
+	info, _ := json.Marshal(recoverySessionInfo{PID: os.Getpid(), StartedAt: now.Format(time.RFC3339)})
+	os.WriteFile(filepath.Join(dir, recoverySessionFile), info, 0600)
+
+	store := &recoveryStore{root: root, dir: dir, pending: make(map[string]BufferSnapshot)}
+	store.collectGarbage()
+
+	recoveryMu.Lock()
+	recovery = store
+	recoveryMu.Unlock()
+}
+
+// stopRecovery löscht die Snapshots der laufenden Sitzung (normales Beenden).
+func stopRecovery() {
+	recoveryMu.Lock()
+	store := recovery
+	recovery = nil
+	recoveryMu.Unlock()
+
+	if store == nil {
+		return
+	}
+	store.mu.Lock()
+	if store.timer != nil {
+		store.timer.Stop()
+	}
+	store.mu.Unlock()
+	os.RemoveAll(store.dir)
+}
+
+// getRecovery liefert den Store der laufenden Sitzung.
+func getRecovery() (*recoveryStore, error) {
+	recoveryMu.Lock()
+	defer recoveryMu.Unlock()
+
+	if recovery == nil {
+		return nil, fmt.Errorf("Wiederherstellung ist nicht verfügbar")
+	}
+	return recovery, nil
+}
+
+// SnapshotBuffer sichert den Inhalt eines ungespeicherten Tabs.
+// Darf bei jeder Änderung aufgerufen werden; geschrieben wird gedrosselt.
+func (a *App) SnapshotBuffer(snapshot BufferSnapshot) error {
+	if snapshot.TabID == "" {
+		return fmt.Errorf("Tab-ID darf nicht leer sein")
+	}
+	store, err := getRecovery()
+	if err != nil {
+		return err
+	}
+
+	snapshot.SavedAt = time.Now().Format(time.RFC3339)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.pending[snapshotFileName(snapshot.TabID, snapshot.Path)] = snapshot
+	if store.timer == nil {
+		store.timer = time.AfterFunc(recoverySnapshotInterval, store.flush)
+	}
+	return nil
+}
+
+// ClearBufferSnapshot entfernt den Snapshot eines Tabs, z.B. nach dem
+// Speichern oder wenn der Tab ohne Speichern geschlossen wurde.
+func (a *App) ClearBufferSnapshot(tabId, path string) error {
+	store, err := getRecovery()
+	if err != nil {
+		return err
+	}
+
+	name := snapshotFileName(tabId, path)
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.pending, name)
+	if err := os.Remove(filepath.Join(store.dir, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Snapshot konnte nicht gelöscht werden: %w", err)
+	}
+	return nil
+}
+
+// GetRecoverableSessions listet abgestürzte Sitzungen mit ungespeicherten
+// Tabs auf (neueste zuerst). Inhalte werden erst mit RestoreSession geladen.
+func (a *App) GetRecoverableSessions() []RecoverySession {
+	sessions := []RecoverySession{}
+	store, err := getRecovery()
+	if err != nil {
+		return sessions
+	}
+
+	entries, err := os.ReadDir(store.root)
+	if err != nil {
+		return sessions
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !store.isRecoverable(entry.Name()) {
+			continue
+		}
+		session, err := store.readSession(entry.Name(), false)
+		if err != nil || len(session.Buffers) == 0 {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt > sessions[j].UpdatedAt
+	})
+	return sessions
+}
+
+// RestoreSession liefert alle Tabs einer abgestürzten Sitzung mit Inhalt.
+// Das Frontend öffnet die Tabs als ungespeichert, sichert sie über
+// SnapshotBuffer in der laufenden Sitzung und ruft erst dann DiscardSession
+// auf. Bis dahin bleibt die Sitzung erhalten, ein erneuter Absturz verliert
+// also nichts.
+func (a *App) RestoreSession(sessionId string) ([]RecoveredBuffer, error) {
+	store, err := getRecovery()
+	if err != nil {
+		return nil, err
+	}
+	if !store.isRecoverable(sessionId) {
+		return nil, fmt.Errorf("Sitzung nicht gefunden: %s", sessionId)
+	}
+
+	session, err := store.readSession(sessionId, true)
+	if err != nil {
+		return nil, err
+	}
+	return session.Buffers, nil
+}
+
+// DiscardSession verwirft eine abgestürzte Sitzung. Wartende Snapshots der
+// laufenden Sitzung werden vorher geschrieben, damit wiederhergestellte Tabs
+// gesichert sind, bevor die alte Sitzung verschwindet.
+func (a *App) DiscardSession(sessionId string) error {
+	store, err := getRecovery()
+	if err != nil {
+		return err
+	}
+	if !store.isRecoverable(sessionId) {
+		return fmt.Errorf("Sitzung nicht gefunden: %s", sessionId)
+	}
+	store.flush()
+	if err := os.RemoveAll(filepath.Join(store.root, sessionId)); err != nil {
+		return fmt.Errorf("Sitzung konnte nicht gelöscht werden: %w", err)
+	}
+	return nil
+}
+
+// flush schreibt alle wartenden Snapshots.
+func (s *recoveryStore) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, snapshot := range s.pending {
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			continue
+		}
+		if err := writeFileAtomic(filepath.Join(s.dir, name), data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Snapshot konnte nicht gespeichert werden: %v\n", err)
+			continue
+		}
+		delete(s.pending, name)
+	}
+
+	s.timer = nil
+	if len(s.pending) > 0 {
+		// Fehlgeschlagene Snapshots später erneut versuchen
+		s.timer = time.AfterFunc(recoverySnapshotInterval, s.flush)
+	}
+}
+
+// isRecoverable prüft, ob sessionID eine fremde, beendete Sitzung ist.
+func (s *recoveryStore) isRecoverable(sessionID string) bool {
+	if sessionID == "" || strings.ContainsAny(sessionID, `/\`) || sessionID == "." || sessionID == ".." {
+		return false
+	}
+	dir := filepath.Join(s.root, sessionID)
+	if dir == s.dir {
+		return false
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, recoverySessionFile))
+	if err != nil {
+		return false
+	}
+	var info recoverySessionInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false
+	}
+	return !processAlive(info.PID)
+}
+
+// readSession liest eine Sitzung. Ohne withContent bleibt Content leer.
+func (s *recoveryStore) readSession(sessionID string, withContent bool) (RecoverySession, error) {
+	dir := filepath.Join(s.root, sessionID)
+	session := RecoverySession{SessionID: sessionID, Buffers: []RecoveredBuffer{}}
+
+	if data, err := os.ReadFile(filepath.Join(dir, recoverySessionFile)); err == nil {
+		var info recoverySessionInfo
+		if json.Unmarshal(data, &info) == nil {
+			session.StartedAt = info.StartedAt
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return session, fmt.Errorf("Sitzung kann nicht gelesen werden: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == recoverySessionFile || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var snapshot BufferSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			continue
+		}
+
+		buffer := RecoveredBuffer{BufferSnapshot: snapshot, Size: len(snapshot.Content)}
+		if snapshot.Path != "" && snapshot.Fingerprint.Hash != "" {
+			buffer.DiskChanged = checkFingerprint(snapshot.Path, snapshot.Fingerprint) != nil
+		}
+		if !withContent {
+			buffer.Content = ""
+		}
+		if snapshot.SavedAt > session.UpdatedAt {
+			session.UpdatedAt = snapshot.SavedAt
+		}
+		session.Buffers = append(session.Buffers, buffer)
+	}
+
+	sort.Slice(session.Buffers, func(i, j int) bool {
+		return session.Buffers[i].SavedAt > session.Buffers[j].SavedAt
+	})
+	return session, nil
+}
+
+// collectGarbage löscht Sitzungen ohne Snapshots und solche, die älter
+// als recoveryMaxAge sind.
+func (s *recoveryStore) collectGarbage() {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !s.isRecoverable(entry.Name()) {
+			continue
+		}
+		dir := filepath.Join(s.root, entry.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		var newest time.Time
+		snapshots := 0
+		for _, f := range files {
+			if f.Name() == recoverySessionFile {
+				continue
+			}
+			snapshots++
+			if info, err := f.Info(); err == nil && info.ModTime().After(newest) {
+				newest = info.ModTime()
+			}
+		}
+		if snapshots == 0 || time.Since(newest) > recoveryMaxAge {
+			os.RemoveAll(dir)
+		}
+	}
+}
+
+// snapshotFileName bildet Tab-ID und Pfad auf einen Dateinamen ab.
+func snapshotFileName(tabID, path string) string {
+	return sha256String(tabID+"\x00"+path)[:16] + ".json"
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+// recoveryProcess.go - Prüfen, ob eine Sitzung noch läuft (siehe recovery.go).
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// processAlive prüft per Signal 0, ob ein Prozess noch läuft.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+// recoveryProcess_windows.go - Prüfen, ob eine Sitzung noch läuft (siehe recovery.go).
+package main
+
+import "syscall"
+
+const (
+	processQueryLimitedInformation = 0x1000
+	processStillActive             = 259 // STILL_ACTIVE
+)
+
+// processAlive prüft über den Exit-Code, ob ein Prozess noch läuft.
+// Fehlen die Rechte zum Öffnen, existiert der Prozess also noch.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == processStillActive
+}
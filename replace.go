@@ -0,0 +1,385 @@
+// replace.go — Projektweites Suchen und Ersetzen.
+// Der Ablauf ist zweistufig:
+//   window.go.main.App.PreviewReplace(req) → Ersetzungsplan (Zeilen-Diffs je Datei)
+//   window.go.main.App.ApplyReplace(req)   → Ausgewählte Teile des Plans schreiben
+//
+// Zwischen Vorschau und Anwendung kann sich eine Datei auf der Festplatte
+// ändern. Deshalb merkt sich der Plan Änderungszeit und Größe jeder Datei;
+// weicht beides beim Anwenden ab, wird die Datei nicht angefasst.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ReplaceRequest: Eingabedaten für die Ersetzungs-Vorschau.
+// Bei Options.Regex kann Replacement Gruppen-Referenzen wie $1 oder ${name}
+// enthalten, sonst wird es wörtlich eingesetzt.
+// Scope grenzt die Dateien ein wie bei der Suche; geöffnete Dateien werden
+// nicht unterstützt, da ApplyReplace auf die Festplatte schreibt.
+type ReplaceRequest struct {
+	RootPath    string        `json:"rootPath"`
+	Options     SearchOptions `json:"options"`
+	Scope       SearchScope   `json:"scope"`
+	Replacement string        `json:"replacement"`
+}
+
+// ReplaceLineDiff beschreibt die Änderung einer einzelnen Zeile.
+type ReplaceLineDiff struct {
+	LineNumber   int    `json:"lineNumber"`
+	OldText      string `json:"oldText"`
+	NewText      string `json:"newText"`
+	Replacements int    `json:"replacements"` // Anzahl Ersetzungen in dieser Zeile
+}
+
+// ReplaceFilePlan enthält alle geplanten Änderungen einer Datei.
+// ModTime und Size halten den Dateizustand zum Zeitpunkt der Vorschau fest.
+type ReplaceFilePlan struct {
+	FilePath     string            `json:"filePath"`
+	FileName     string            `json:"fileName"`
+	ModTime      string            `json:"modTime"` // RFC3339Nano
+	Size         int64             `json:"size"`
+	Lines        []ReplaceLineDiff `json:"lines"`
+	Replacements int               `json:"replacements"`
+}
+
+// ReplacePlan ist das Ergebnis von PreviewReplace.
+type ReplacePlan struct {
+	RootPath          string            `json:"rootPath"`
+	Options           SearchOptions     `json:"options"`
+	Replacement       string            `json:"replacement"`
+	Files             []ReplaceFilePlan `json:"files"`
+	TotalReplacements int               `json:"totalReplacements"`
+	Error             string            `json:"error"`
+}
+
+// ReplaceSelection wählt Zeilen einer Datei aus dem Plan aus.
+// Ist LineNumbers leer, werden alle Zeilen der Datei übernommen.
+type ReplaceSelection struct {
+	FilePath    string `json:"filePath"`
+	LineNumbers []int  `json:"lineNumbers"`
+}
+
+// ApplyReplaceRequest: Eingabedaten für ApplyReplace.
+// Ist Selection leer, wird der gesamte Plan angewendet.
+type ApplyReplaceRequest struct {
+	Plan      ReplacePlan        `json:"plan"`
+	Selection []ReplaceSelection `json:"selection"`
+}
+
+// ReplaceConflict beschreibt eine Datei, die nicht geschrieben wurde.
+type ReplaceConflict struct {
+	FilePath string `json:"filePath"`
+	Reason   string `json:"reason"`
+}
+
+// ApplyReplaceResult ist das Ergebnis von ApplyReplace.
+type ApplyReplaceResult struct {
+	FilesChanged []string          `json:"filesChanged"`
+	Replacements int               `json:"replacements"`
+	Conflicts    []ReplaceConflict `json:"conflicts"`
+	Error        string            `json:"error"`
+}
+
+// PreviewReplace berechnet einen Ersetzungsplan, ohne Dateien zu verändern.
+func (a *App) PreviewReplace(req ReplaceRequest) ReplacePlan {
+	if req.Options.Query == "" {
+		return ReplacePlan{Error: "Suchbegriff darf nicht leer sein"}
+	}
+
+	re, err := newReplacePattern(req.Options)
+	if err != nil {
+		return ReplacePlan{Error: err.Error()}
+	}
+
+	rootPath, err := filepath.Abs(req.RootPath)
+	if err != nil {
+		return ReplacePlan{Error: "Ungültiger Pfad: " + err.Error()}
+	}
+
+	if req.Scope.Mode == SearchScopeOpenFiles {
+		return ReplacePlan{Error: "Ersetzen in geöffneten Dateien wird nicht unterstützt"}
+	}
+	scope, err := resolveSearchScope(rootPath, req.Scope)
+	if err != nil {
+		return ReplacePlan{Error: err.Error()}
+	}
+
+	plan := ReplacePlan{
+		RootPath:    rootPath,
+		Options:     req.Options,
+		Replacement: req.Replacement,
+		Files:       []ReplaceFilePlan{},
+	}
+
+	err = walkSearchableFiles(scope.walkRoot, defaultMaxSearchFileSize, func(path string, info os.FileInfo) error {
+		if !scope.allows(path) {
+			return nil
+		}
+		content, _, ok := readReplaceText(path)
+		if !ok {
+			return nil
+		}
+
+		lines := replaceLines(splitLinesKeepEOL(content), re, req.Replacement, req.Options.Regex)
+		if len(lines) == 0 {
+			return nil
+		}
+
+		filePlan := ReplaceFilePlan{
+			FilePath: path,
+			FileName: filepath.Base(path),
+			ModTime:  info.ModTime().Format(time.RFC3339Nano),
+			Size:     info.Size(),
+			Lines:    lines,
+		}
+		for _, l := range lines {
+			filePlan.Replacements += l.Replacements
+		}
+		plan.Files = append(plan.Files, filePlan)
+		plan.TotalReplacements += filePlan.Replacements
+		return nil
+	})
+
+	if err != nil {
+		plan.Error = "Suchfehler: " + err.Error()
+	}
+
+	return plan
+}
+
+// ApplyReplace schreibt die ausgewählten Ersetzungen eines Plans.
+// Jede Datei wird einzeln atomar geschrieben (siehe writeFileAtomic).
+// Dateien, die sich seit der Vorschau geändert haben oder außerhalb des
+// geöffneten Projekts liegen, werden übersprungen und als Konflikt gemeldet.
+func (a *App) ApplyReplace(req ApplyReplaceRequest) ApplyReplaceResult {
+	plan := req.Plan
+	if plan.Options.Query == "" {
+		return ApplyReplaceResult{Error: "Ungültiger Ersetzungsplan"}
+	}
+	projectRoot := currentProjectRoot()
+	if projectRoot == "" {
+		return ApplyReplaceResult{Error: "Kein Projekt geöffnet"}
+	}
+
+	re, err := newReplacePattern(plan.Options)
+	if err != nil {
+		return ApplyReplaceResult{Error: err.Error()}
+	}
+
+	// Auswahl nach Dateipfad indizieren (nil = alle Zeilen)
+	var selected map[string]map[int]bool
+	if len(req.Selection) > 0 {
+		selected = make(map[string]map[int]bool)
+		for _, sel := range req.Selection {
+			var lineSet map[int]bool
+			if len(sel.LineNumbers) > 0 {
+				lineSet = make(map[int]bool)
+				for _, n := range sel.LineNumbers {
+					lineSet[n] = true
+				}
+			}
+			selected[sel.FilePath] = lineSet
+		}
+	}
+
+	result := ApplyReplaceResult{
+		FilesChanged: []string{},
+		Conflicts:    []ReplaceConflict{},
+	}
+
+	for _, filePlan := range plan.Files {
+		var lineSet map[int]bool
+		if selected != nil {
+			ls, ok := selected[filePlan.FilePath]
+			if !ok {
+				continue
+			}
+			lineSet = ls
+		}
+
+		path, err := resolveProjectPath(filePlan.FilePath, projectRoot, true)
+		if err != nil {
+			result.Conflicts = append(result.Conflicts, ReplaceConflict{
+				FilePath: filePlan.FilePath,
+				Reason:   err.Error(),
+			})
+			continue
+		}
+		filePlan.FilePath = path
+
+		count, err := applyFilePlan(filePlan, lineSet, re, plan.Replacement, plan.Options.Regex)
+		if err != nil {
+			result.Conflicts = append(result.Conflicts, ReplaceConflict{
+				FilePath: filePlan.FilePath,
+				Reason:   err.Error(),
+			})
+			continue
+		}
+		if count > 0 {
+			result.FilesChanged = append(result.FilesChanged, filePlan.FilePath)
+			result.Replacements += count
+		}
+	}
+
+	return result
+}
+
+// newReplacePattern kompiliert den Suchausdruck für das Ersetzen.
+// Ausdrücke, die den leeren String treffen (z.B. "a*"), werden abgelehnt,
+// da sie zwischen jedem Zeichen eine Ersetzung einfügen würden.
+func newReplacePattern(opts SearchOptions) (*regexp.Regexp, error) {
+	if opts.MatchAllTerms {
+		return nil, fmt.Errorf("Ersetzen ist im Modus \"alle Begriffe\" nicht möglich")
+	}
+
+	matcher, err := newSearchMatcher(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	re := matcher.patterns[0]
+	if re.MatchString("") {
+		return nil, fmt.Errorf("Suchausdruck darf keine leeren Treffer liefern")
+	}
+	return re, nil
+}
+
+// applyFilePlan wendet die ausgewählten Zeilen eines Dateiplans an.
+// lineSet == nil bedeutet: alle Zeilen des Plans.
+// Gibt die Anzahl der durchgeführten Ersetzungen zurück.
+func applyFilePlan(filePlan ReplaceFilePlan, lineSet map[int]bool, re *regexp.Regexp, replacement string, isRegex bool) (int, error) {
+	info, err := os.Stat(filePlan.FilePath)
+	if err != nil {
+		return 0, fmt.Errorf("Datei nicht lesbar: %v", err)
+	}
+	if info.ModTime().Format(time.RFC3339Nano) != filePlan.ModTime || info.Size() != filePlan.Size {
+		return 0, fmt.Errorf("Datei wurde seit der Vorschau geändert")
+	}
+
+	content, format, ok := readReplaceText(filePlan.FilePath)
+	if !ok {
+		return 0, fmt.Errorf("Datei nicht lesbar")
+	}
+	lines := splitLinesKeepEOL(content)
+
+	count := 0
+	for _, diff := range filePlan.Lines {
+		if lineSet != nil && !lineSet[diff.LineNumber] {
+			continue
+		}
+		idx := diff.LineNumber - 1
+		if idx < 0 || idx >= len(lines) {
+			return 0, fmt.Errorf("Zeile %d existiert nicht mehr", diff.LineNumber)
+		}
+
+		text, eol := splitEOL(lines[idx])
+		if text != diff.OldText {
+			return 0, fmt.Errorf("Zeile %d stimmt nicht mehr mit der Vorschau überein", diff.LineNumber)
+		}
+		// Ersetzung und Anzahl neu berechnen statt NewText und
+		// Replacements zu übernehmen, damit der Plan vom Frontend nicht
+		// beliebigen Inhalt einschleusen kann.
+		lines[idx] = replaceInLine(text, re, replacement, isRegex) + eol
+		count += countMatches(re, text)
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	// In der Kodierung der Datei zurückschreiben; die Zeilenenden stehen
+	// bereits unverändert in lines
+	data, err := encodeForSave(filePlan.FilePath, strings.Join(lines, ""), TextFormat{Encoding: format.Encoding, BOM: format.BOM})
+	if err != nil {
+		return 0, err
+	}
+	recordFileHistory(filePlan.FilePath)
+	if err := writeFileAtomic(filePlan.FilePath, data); err != nil {
+		return 0, err
+	}
+	markSearchIndexDirty(filePlan.FilePath)
+	return count, nil
+}
+
+// readReplaceText liest und dekodiert eine Datei wie der Editor (siehe
+// textEncoding.go). Binärdaten werden erst nach dem Dekodieren erkannt,
+// damit UTF-16-Dateien mit ihren Null-Bytes nicht übersprungen werden.
+// ok ist false für Binärdateien und nicht lesbare Dateien.
+func readReplaceText(path string) (string, TextFormat, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", TextFormat{}, false
+	}
+	content, format := decodeTextFile(data)
+	if isBinaryContent([]byte(content[:min(len(content), binarySniffLen)])) {
+		return "", TextFormat{}, false
+	}
+	return content, format, true
+}
+
+// replaceLines berechnet die Zeilen-Diffs für den Inhalt einer Datei.
+func replaceLines(lines []string, re *regexp.Regexp, replacement string, isRegex bool) []ReplaceLineDiff {
+	var diffs []ReplaceLineDiff
+	for i, line := range lines {
+		text, _ := splitEOL(line)
+		n := countMatches(re, text)
+		if n == 0 {
+			continue
+		}
+		diffs = append(diffs, ReplaceLineDiff{
+			LineNumber:   i + 1,
+			OldText:      text,
+			NewText:      replaceInLine(text, re, replacement, isRegex),
+			Replacements: n,
+		})
+	}
+	return diffs
+}
+
+// replaceInLine ersetzt alle Treffer in einer Zeile.
+// Nur im Regex-Modus werden $1, ${name} usw. ausgewertet.
+func replaceInLine(text string, re *regexp.Regexp, replacement string, isRegex bool) string {
+	if isRegex {
+		return re.ReplaceAllString(text, replacement)
+	}
+	return re.ReplaceAllLiteralString(text, replacement)
+}
+
+// countMatches zählt die nicht-leeren Treffer in einer Zeile.
+func countMatches(re *regexp.Regexp, text string) int {
+	n := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		if loc[1] > loc[0] {
+			n++
+		}
+	}
+	return n
+}
+
+// splitLinesKeepEOL teilt Text in Zeilen auf, wobei jede Zeile ihr
+// Zeilenende ("\n" oder "\r\n") behält. So lässt sich der Text nach
+// dem Ersetzen byte-genau wieder zusammensetzen.
+func splitLinesKeepEOL(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitEOL trennt das Zeilenende von einer Zeile ab.
+func splitEOL(line string) (string, string) {
+	if strings.HasSuffix(line, "\r\n") {
+		return line[:len(line)-2], "\r\n"
+	}
+	if strings.HasSuffix(line, "\n") {
+		return line[:len(line)-1], "\n"
+	}
+	return line, ""
+}
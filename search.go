@@ -3,19 +3,53 @@
 package main
 
 import (
-	"bufio"
-	"os"
+	"context"
+	"fmt"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // SearchMatch beschreibt einen Treffer in einer Datei.
+// Alle Positionen sind in UTF-16-Einheiten angegeben, damit sie direkt mit
+// JavaScript-Strings und CodeMirror verwendet werden können.
+// LineText ist für die Anzeige gekürzt; MatchStart und Ranges beziehen sich
+// auf LineText, Columns auf die vollständige Zeile in der Datei.
 type SearchMatch struct {
-	FilePath   string `json:"filePath"`
-	FileName   string `json:"fileName"`
-	LineNumber int    `json:"lineNumber"`
-	LineText   string `json:"lineText"`
-	MatchStart int    `json:"matchStart"` // Position des Treffers in der Zeile
+	FilePath      string       `json:"filePath"`
+	FileName      string       `json:"fileName"`
+	LineNumber    int          `json:"lineNumber"`
+	LineText      string       `json:"lineText"`
+	MatchStart    int          `json:"matchStart"`    // Position des ersten Treffers in LineText
+	Ranges        []MatchRange `json:"ranges"`        // Alle Treffer, bezogen auf LineText
+	Columns       []MatchRange `json:"columns"`       // Alle Treffer, bezogen auf die Originalzeile
+	ContextBefore []string     `json:"contextBefore"` // Zeilen vor dem Treffer (gekürzt)
+	ContextAfter  []string     `json:"contextAfter"`  // Zeilen nach dem Treffer (gekürzt)
+}
+
+// MatchRange beschreibt einen einzelnen Treffer innerhalb einer Zeile.
+// End ist exklusiv.
+type MatchRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchOptions steuert, wie der Suchbegriff interpretiert wird.
+//
+//	Regex:         Query ist ein regulärer Ausdruck (Go RE2-Syntax)
+//	WholeWord:     Nur ganze Wörter finden (Wortgrenzen an beiden Enden)
+//	MatchAllTerms: Query wird an Leerzeichen aufgeteilt, eine Zeile trifft
+//	               nur, wenn alle Begriffe darin vorkommen
+//	ContextLines:  Anzahl Zeilen vor und nach jedem Treffer (max. 10)
+type SearchOptions struct {
+	Query         string `json:"query"`
+	CaseSensitive bool   `json:"caseSensitive"`
+	Regex         bool   `json:"regex"`
+	WholeWord     bool   `json:"wholeWord"`
+	MatchAllTerms bool   `json:"matchAllTerms"`
+	ContextLines  int    `json:"contextLines"`
 }
 
 // SearchResult ist das Ergebnis einer Suche.
@@ -30,161 +64,290 @@ type SearchResult struct {
 // Maximale Anzahl von Treffern um Performance zu schützen
 const maxSearchMatches = 500
 
-// Dateierweiterungen die durchsucht werden (Textdateien)
-var searchableExtensions = map[string]bool{
-	".txt": true, ".md": true, ".json": true, ".xml": true, ".yaml": true, ".yml": true,
-	".js": true, ".ts": true, ".jsx": true, ".tsx": true, ".mjs": true, ".cjs": true,
-	".html": true, ".htm": true, ".css": true, ".scss": true, ".sass": true, ".less": true,
-	".py": true, ".go": true, ".java": true, ".c": true, ".cpp": true, ".h": true, ".hpp": true,
-	".rs": true, ".rb": true, ".php": true, ".sh": true, ".bash": true, ".zsh": true,
-	".sql": true, ".graphql": true, ".vue": true, ".svelte": true,
-	".env": true, ".gitignore": true, ".dockerignore": true,
-	".toml": true, ".ini": true, ".cfg": true, ".conf": true,
+// Dateien über dieser Größe werden standardmäßig nicht durchsucht
+const defaultMaxSearchFileSize = 1024 * 1024
+
+// Maximale Länge einer angezeigten Zeile (Bytes) und Anzahl Kontextzeilen
+const (
+	maxDisplayLineLength = 200
+	maxContextLines      = 10
+)
+
+// SearchFileGroup fasst die Treffer einer Datei zusammen.
+type SearchFileGroup struct {
+	FilePath   string        `json:"filePath"`
+	FileName   string        `json:"fileName"`
+	MatchCount int           `json:"matchCount"` // Anzahl Treffer (nicht Zeilen)
+	Matches    []SearchMatch `json:"matches"`
 }
 
-// Ordner die übersprungen werden
-var skipDirectories = map[string]bool{
-	"node_modules": true, ".git": true, ".svn": true, ".hg": true,
-	"vendor": true, "dist": true, "build": true, ".next": true,
-	"__pycache__": true, ".pytest_cache": true, ".tox": true,
-	"target": true, "bin": true, "obj": true,
+// GroupedSearchResult ist das Ergebnis von SearchInDirectoryGrouped.
+type GroupedSearchResult struct {
+	Query        string            `json:"query"`
+	RootPath     string            `json:"rootPath"`
+	Files        []SearchFileGroup `json:"files"`
+	TotalMatches int               `json:"totalMatches"`
+	TotalFiles   int               `json:"totalFiles"` // Durchsuchte Dateien
+	Error        string            `json:"error"`
 }
 
-// SearchInDirectory durchsucht alle Textdateien in einem Verzeichnis.
+// SearchInDirectory durchsucht alle Textdateien in einem Verzeichnis
+// nach einem einfachen Suchbegriff.
 func (a *App) SearchInDirectory(rootPath, query string, caseSensitive bool) SearchResult {
-	if query == "" {
+	return a.SearchInDirectoryWithOptions(rootPath, SearchOptions{
+		Query:         query,
+		CaseSensitive: caseSensitive,
+	})
+}
+
+// SearchInDirectoryWithOptions durchsucht alle Textdateien in einem Verzeichnis
+// mit erweiterten Optionen (Regex, ganze Wörter, alle Begriffe).
+func (a *App) SearchInDirectoryWithOptions(rootPath string, opts SearchOptions) SearchResult {
+	return a.SearchInScope(SearchRequest{RootPath: rootPath, Options: opts})
+}
+
+// SearchInScope durchsucht den in req.Scope gewählten Bereich
+// (Unterordner, geöffnete Dateien, include/exclude-Globs).
+func (a *App) SearchInScope(req SearchRequest) SearchResult {
+	opts := req.Options
+	if opts.Query == "" {
 		return SearchResult{Error: "Suchbegriff darf nicht leer sein"}
 	}
 
-	rootPath, err := filepath.Abs(rootPath)
+	matcher, err := newSearchMatcher(opts)
+	if err != nil {
+		return SearchResult{Error: err.Error()}
+	}
+
+	rootPath, err := filepath.Abs(req.RootPath)
 	if err != nil {
 		return SearchResult{Error: "Ungültiger Pfad: " + err.Error()}
 	}
 
+	scope, err := resolveSearchScope(rootPath, req.Scope)
+	if err != nil {
+		return SearchResult{Error: err.Error()}
+	}
+
 	result := SearchResult{
-		Query:    query,
+		Query:    opts.Query,
 		RootPath: rootPath,
 		Matches:  []SearchMatch{},
 	}
 
-	searchQuery := query
-	if !caseSensitive {
-		searchQuery = strings.ToLower(query)
+	err = runParallelSearch(context.Background(), scope, matcher, defaultMaxSearchFileSize,
+		func(path string, matches []SearchMatch) bool {
+			result.Matches = append(result.Matches, matches...)
+			result.TotalFiles++
+
+			// Limit erreicht?
+			return len(result.Matches) < maxSearchMatches
+		})
+
+	if err != nil {
+		result.Error = "Suchfehler: " + err.Error()
 	}
 
-	err = filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Fehler ignorieren, weitermachen
-		}
+	return result
+}
 
-		// Ordner überspringen
-		if info.IsDir() {
-			if skipDirectories[info.Name()] {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+// SearchInDirectoryGrouped sucht wie SearchInDirectoryWithOptions, liefert
+// die Treffer aber nach Datei gruppiert.
+func (a *App) SearchInDirectoryGrouped(rootPath string, opts SearchOptions) GroupedSearchResult {
+	return a.SearchInScopeGrouped(SearchRequest{RootPath: rootPath, Options: opts})
+}
 
-		// Versteckte Dateien überspringen
-		if strings.HasPrefix(info.Name(), ".") && info.Name() != ".env" &&
-		   info.Name() != ".gitignore" && info.Name() != ".dockerignore" {
-			return nil
+// SearchInScopeGrouped sucht wie SearchInScope, liefert die Treffer aber
+// nach Datei gruppiert.
+func (a *App) SearchInScopeGrouped(req SearchRequest) GroupedSearchResult {
+	result := a.SearchInScope(req)
+	grouped := GroupedSearchResult{
+		Query:      result.Query,
+		RootPath:   result.RootPath,
+		Files:      groupSearchMatches(result.Matches),
+		TotalFiles: result.TotalFiles,
+		Error:      result.Error,
+	}
+	for _, g := range grouped.Files {
+		grouped.TotalMatches += g.MatchCount
+	}
+	return grouped
+}
+
+// groupSearchMatches gruppiert Treffer nach Datei. Die Reihenfolge der
+// Dateien und Treffer bleibt erhalten.
+func groupSearchMatches(matches []SearchMatch) []SearchFileGroup {
+	groups := []SearchFileGroup{}
+	index := make(map[string]int)
+	for _, m := range matches {
+		i, ok := index[m.FilePath]
+		if !ok {
+			i = len(groups)
+			index[m.FilePath] = i
+			groups = append(groups, SearchFileGroup{
+				FilePath: m.FilePath,
+				FileName: m.FileName,
+				Matches:  []SearchMatch{},
+			})
 		}
+		groups[i].Matches = append(groups[i].Matches, m)
+		groups[i].MatchCount += len(m.Columns)
+	}
+	return groups
+}
 
-		// Nur durchsuchbare Dateien
-		ext := strings.ToLower(filepath.Ext(path))
-		if ext == "" {
-			// Dateien ohne Extension prüfen (Dockerfile, Makefile, etc.)
-			baseName := strings.ToLower(info.Name())
-			if baseName != "dockerfile" && baseName != "makefile" &&
-			   baseName != "readme" && baseName != "license" {
-				return nil
-			}
-		} else if !searchableExtensions[ext] {
-			return nil
+// searchMatcher prüft Zeilen gegen einen oder mehrere kompilierte Ausdrücke.
+// Alle Suchmodi werden auf reguläre Ausdrücke abgebildet, damit Treffer-
+// Positionen einheitlich ermittelt werden können.
+type searchMatcher struct {
+	patterns     []*regexp.Regexp
+	contextLines int
+}
+
+// newSearchMatcher übersetzt die Suchoptionen in reguläre Ausdrücke.
+func newSearchMatcher(opts SearchOptions) (*searchMatcher, error) {
+	terms := []string{opts.Query}
+	if opts.MatchAllTerms {
+		terms = strings.Fields(opts.Query)
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("Suchbegriff darf nicht leer sein")
 		}
+	}
 
-		// Zu große Dateien überspringen (> 1MB)
-		if info.Size() > 1024*1024 {
-			return nil
+	m := &searchMatcher{contextLines: min(max(opts.ContextLines, 0), maxContextLines)}
+	for _, term := range terms {
+		expr := term
+		if !opts.Regex {
+			expr = regexp.QuoteMeta(term)
+		}
+		if opts.WholeWord {
+			expr = `\b(?:` + expr + `)\b`
+		}
+		if !opts.CaseSensitive {
+			expr = "(?i)" + expr
 		}
 
-		// Datei durchsuchen
-		matches := searchFile(path, searchQuery, caseSensitive)
-		result.Matches = append(result.Matches, matches...)
-		result.TotalFiles++
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("Ungültiger regulärer Ausdruck: %v", err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
 
-		// Limit erreicht?
-		if len(result.Matches) >= maxSearchMatches {
-			return filepath.SkipAll
+// matchLine gibt alle Treffer-Bereiche in einer Zeile zurück (sortiert nach Start).
+// Bei mehreren Begriffen muss jeder Begriff mindestens einmal vorkommen,
+// sonst wird nil zurückgegeben.
+func (m *searchMatcher) matchLine(line string) []MatchRange {
+	var ranges []MatchRange
+	for _, re := range m.patterns {
+		locs := re.FindAllStringIndex(line, -1)
+		found := false
+		for _, loc := range locs {
+			// Leere Treffer (z.B. bei "a*") sind nicht sinnvoll darstellbar
+			if loc[1] > loc[0] {
+				ranges = append(ranges, MatchRange{Start: loc[0], End: loc[1]})
+				found = true
+			}
 		}
+		if !found {
+			return nil
+		}
+	}
 
-		return nil
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
 	})
+	return ranges
+}
 
-	if err != nil && err != filepath.SkipAll {
-		result.Error = "Suchfehler: " + err.Error()
+// buildDisplayLine kürzt eine Zeile für die Anzeige und rechnet die
+// Treffer-Bereiche (Byte-Offsets in lineText) in UTF-16-Offsets im
+// gekürzten Text um. Führende/abschließende Leerzeichen werden entfernt,
+// bei zu langen Zeilen bleibt der erste Treffer sichtbar. Geschnitten wird
+// nur an Zeichengrenzen, nie mitten in einem UTF-8-Zeichen.
+// Treffer, die außerhalb des sichtbaren Ausschnitts liegen, werden abgeschnitten.
+func buildDisplayLine(lineText string, ranges []MatchRange) (string, []MatchRange) {
+	start := len(lineText) - len(strings.TrimLeft(lineText, " \t"))
+	end := len(strings.TrimRight(lineText, " \t"))
+	if start > ranges[0].Start {
+		start = ranges[0].Start
+	}
+	if end < start {
+		end = start
 	}
 
-	return result
-}
-
-// searchFile durchsucht eine einzelne Datei.
-func searchFile(filePath, query string, caseSensitive bool) []SearchMatch {
-	var matches []SearchMatch
+	prefix, suffix := "", ""
+	if end-start > maxDisplayLineLength {
+		// Versuche den Match sichtbar zu halten
+		if ranges[0].Start-50 > start {
+			start = runeStartBefore(lineText, ranges[0].Start-50)
+			prefix = "..."
+		}
+		if start+maxDisplayLineLength < end {
+			end = runeStartBefore(lineText, start+maxDisplayLineLength)
+			suffix = "..."
+		}
+	}
 
-	file, err := os.Open(filePath)
-	if err != nil {
-		return matches
+	visible := lineText[start:end]
+	shift := utf16Len(prefix)
+	displayRanges := make([]MatchRange, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Start >= end {
+			break
+		}
+		displayRanges = append(displayRanges, MatchRange{
+			Start: shift + utf16Len(visible[:max(r.Start, start)-start]),
+			End:   shift + utf16Len(visible[:min(r.End, end)-start]),
+		})
 	}
-	defer file.Close()
 
-	fileName := filepath.Base(filePath)
-	scanner := bufio.NewScanner(file)
-	lineNumber := 0
+	return prefix + visible + suffix, displayRanges
+}
 
-	for scanner.Scan() {
-		lineNumber++
-		lineText := scanner.Text()
+// truncateDisplayLine kürzt eine Kontextzeile zeichengenau auf die
+// maximale Anzeigelänge.
+func truncateDisplayLine(line string) string {
+	line = strings.TrimRight(line, " \t")
+	if len(line) <= maxDisplayLineLength {
+		return line
+	}
+	return line[:runeStartBefore(line, maxDisplayLineLength)] + "..."
+}
 
-		searchLine := lineText
-		if !caseSensitive {
-			searchLine = strings.ToLower(lineText)
+// toUTF16Ranges rechnet Byte-Offsets in line in UTF-16-Offsets um.
+func toUTF16Ranges(line string, ranges []MatchRange) []MatchRange {
+	converted := make([]MatchRange, len(ranges))
+	for i, r := range ranges {
+		converted[i] = MatchRange{
+			Start: utf16Len(line[:r.Start]),
+			End:   utf16Len(line[:r.End]),
 		}
+	}
+	return converted
+}
 
-		matchStart := strings.Index(searchLine, query)
-		if matchStart >= 0 {
-			// Zeile kürzen wenn zu lang
-			displayText := lineText
-			if len(displayText) > 200 {
-				// Versuche den Match sichtbar zu halten
-				start := matchStart - 50
-				if start < 0 {
-					start = 0
-				}
-				end := start + 200
-				if end > len(displayText) {
-					end = len(displayText)
-				}
-				displayText = displayText[start:end]
-				if start > 0 {
-					displayText = "..." + displayText
-					matchStart = matchStart - start + 3
-				}
-				if end < len(lineText) {
-					displayText = displayText + "..."
-				}
-			}
+// runeStartBefore gibt den größten Offset <= i zurück, an dem in s ein
+// UTF-8-Zeichen beginnt.
+func runeStartBefore(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
 
-			matches = append(matches, SearchMatch{
-				FilePath:   filePath,
-				FileName:   fileName,
-				LineNumber: lineNumber,
-				LineText:   strings.TrimSpace(displayText),
-				MatchStart: matchStart,
-			})
+// utf16Len gibt die Länge eines Strings in UTF-16-Einheiten zurück
+// (Zeichen außerhalb der BMP, z.B. Emojis, zählen doppelt).
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
 		}
 	}
-
-	return matches
+	return n
 }
@@ -0,0 +1,357 @@
+// searchEngine.go — Parallele Suche über viele Dateien.
+// Der Verzeichnisbaum wird von einer Goroutine durchlaufen, ein Pool aus
+// Workern durchsucht die Dateien. Die Ergebnisse werden anschließend wieder
+// in Durchlauf-Reihenfolge (Pfad, dann Zeile) gebracht, sodass eine Suche
+// unabhängig von der Worker-Anzahl immer dieselbe Trefferliste liefert.
+//
+// Ob eine Datei Text enthält, wird anhand der ersten Bytes erkannt
+// (isBinaryContent) — nicht über eine Liste von Dateiendungen.
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Anzahl Bytes am Dateianfang, die zur Binär-Erkennung geprüft werden
+const binarySniffLen = 8000
+
+// searchTask ist eine Datei, die ein Worker durchsuchen soll.
+// seq ist die Position im Verzeichnisdurchlauf. Ist buffer gesetzt, wird
+// dessen Inhalt statt der Datei auf der Festplatte durchsucht.
+type searchTask struct {
+	seq    int
+	path   string
+	buffer *OpenBuffer
+}
+
+// searchTaskResult ist das Ergebnis eines Workers für eine Datei.
+// searched ist false, wenn die Datei binär oder nicht lesbar war.
+type searchTaskResult struct {
+	seq      int
+	path     string
+	matches  []SearchMatch
+	searched bool
+}
+
+// runParallelSearch durchsucht alle Textdateien im Suchbereich mit einem
+// Worker-Pool. Gibt es einen Index für das Projekt, werden nur dessen
+// Kandidaten gelesen; bei geöffneten Dateien nur deren Inhalt (in Tab-
+// Reihenfolge). onFile wird für jede durchsuchte Datei in
+// Durchlauf-Reihenfolge aufgerufen (nie parallel); gibt es false zurück,
+// wird die Suche beendet. Ein Abbruch über ctx gilt nicht als Fehler.
+func runParallelSearch(ctx context.Context, scope *searchScope, matcher *searchMatcher, maxFileSize int64,
+	onFile func(path string, matches []SearchMatch) bool) error {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := runtime.NumCPU()
+	tasks := make(chan searchTask, workers*4)
+	results := make(chan searchTaskResult, workers*4)
+
+	// Kandidaten aus dem Projekt-Index, falls vorhanden (siehe searchIndex.go)
+	var candidates []string
+	indexed := false
+	if !scope.onlyOpen {
+		candidates, indexed = indexedSearchCandidates(scope.walkRoot, matcher, maxFileSize)
+	}
+
+	// Verzeichnisdurchlauf bzw. Kandidatenliste: verteilt Dateien an die Worker
+	var walkErr error
+	walkDone := make(chan struct{})
+	go func() {
+		defer close(walkDone)
+		defer close(tasks)
+		seq := 0
+		emit := func(task searchTask) error {
+			task.seq = seq
+			select {
+			case tasks <- task:
+				seq++
+				return nil
+			case <-ctx.Done():
+				return filepath.SkipAll
+			}
+		}
+
+		if scope.onlyOpen {
+			for i := range scope.buffers {
+				buf := &scope.buffers[i]
+				if emit(searchTask{path: buf.Path, buffer: buf}) != nil {
+					return
+				}
+			}
+			return
+		}
+
+		if indexed {
+			for _, path := range candidates {
+				if !scope.allows(path) {
+					continue
+				}
+				if emit(searchTask{path: path}) != nil {
+					return
+				}
+			}
+			return
+		}
+
+		walkErr = walkSearchableFiles(scope.walkRoot, maxFileSize, func(path string, info os.FileInfo) error {
+			if !scope.allows(path) {
+				return nil
+			}
+			return emit(searchTask{path: path})
+		})
+		if walkErr == filepath.SkipAll {
+			walkErr = nil
+		}
+	}()
+
+	// Worker-Pool
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range tasks {
+				var matches []SearchMatch
+				var searched bool
+				if task.buffer != nil {
+					matches = searchContent(task.path, task.buffer.Content, matcher)
+					searched = true
+				} else {
+					matches, searched = searchFile(task.path, matcher)
+				}
+				select {
+				case results <- searchTaskResult{seq: task.seq, path: task.path, matches: matches, searched: searched}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Ergebnisse in Durchlauf-Reihenfolge weitergeben. Vorzeitig fertige
+	// Dateien warten in pending, bis alle Vorgänger geliefert wurden.
+	pending := make(map[int]searchTaskResult)
+	next := 0
+	stopped := false
+	for r := range results {
+		if stopped {
+			continue // Restliche Ergebnisse verwerfen
+		}
+		pending[r.seq] = r
+		for {
+			p, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			if p.searched && !onFile(p.path, p.matches) {
+				stopped = true
+				cancel()
+				break
+			}
+		}
+	}
+
+	<-walkDone
+	return walkErr
+}
+
+// walkSearchableFiles ruft fn für jede Datei unterhalb von rootPath auf,
+// die durchsucht werden soll. Per .gitignore oder Projektkonfiguration
+// ausgeschlossene Pfade, versteckte Dateien und Dateien über maxFileSize
+// Bytes werden übersprungen.
+// Gibt fn filepath.SkipAll zurück, wird der Durchlauf beendet.
+func walkSearchableFiles(rootPath string, maxFileSize int64, fn func(path string, info os.FileInfo) error) error {
+	filter := newProjectPathFilter(rootPath, defaultSearchExcludes)
+
+	// Liegt rootPath selbst in einem ausgeschlossenen Ordner, wird trotzdem
+	// gesucht — der Benutzer hat den Ordner ausdrücklich gewählt.
+	return filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Fehler ignorieren, weitermachen
+		}
+		if path == rootPath {
+			return nil
+		}
+
+		rel, ok := filter.relPath(path)
+		if ok && filter.excludesEntry(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+		if !filter.isIncluded(path) {
+			return nil
+		}
+
+		// Nur reguläre Dateien (keine Sockets, Pipes, Geräte)
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		// Versteckte Dateien überspringen
+		if strings.HasPrefix(info.Name(), ".") && info.Name() != ".env" &&
+			info.Name() != ".gitignore" && info.Name() != ".dockerignore" {
+			return nil
+		}
+
+		// Zu große Dateien überspringen
+		if info.Size() > maxFileSize {
+			return nil
+		}
+
+		return fn(path, info)
+	})
+}
+
+// searchFile durchsucht eine einzelne Datei.
+// Zeilen werden ohne Längenbegrenzung gelesen (bufio.Scanner würde bei
+// Zeilen über 64KB abbrechen). Gibt false zurück, wenn die Datei binär
+// oder nicht lesbar ist.
+func searchFile(filePath string, matcher *searchMatcher) ([]SearchMatch, bool) {
+	var matches []SearchMatch
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return matches, false
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	head, _ := reader.Peek(binarySniffLen)
+	if isBinaryContent(head) {
+		return matches, false
+	}
+
+	return searchLines(filePath, reader, matcher)
+}
+
+// searchContent durchsucht den Inhalt einer geöffneten Datei.
+func searchContent(filePath, content string, matcher *searchMatcher) []SearchMatch {
+	matches, _ := searchLines(filePath, bufio.NewReader(strings.NewReader(content)), matcher)
+	return matches
+}
+
+// searchLines durchsucht alle Zeilen aus reader.
+// Gibt false zurück, wenn schon die erste Zeile nicht lesbar ist.
+func searchLines(filePath string, reader *bufio.Reader, matcher *searchMatcher) ([]SearchMatch, bool) {
+	var matches []SearchMatch
+	fileName := filepath.Base(filePath)
+	lineNumber := 0
+
+	// Kontext: die letzten N Zeilen und Treffer, die noch Folgezeilen brauchen
+	contextLines := matcher.contextLines
+	var before []string
+	var waitingForAfter []int
+
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			lineNumber++
+			lineText := strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+			if contextLines > 0 && len(waitingForAfter) > 0 {
+				display := truncateDisplayLine(lineText)
+				remaining := waitingForAfter[:0]
+				for _, i := range waitingForAfter {
+					matches[i].ContextAfter = append(matches[i].ContextAfter, display)
+					if len(matches[i].ContextAfter) < contextLines {
+						remaining = append(remaining, i)
+					}
+				}
+				waitingForAfter = remaining
+			}
+
+			if ranges := matcher.matchLine(lineText); len(ranges) > 0 {
+				displayText, displayRanges := buildDisplayLine(lineText, ranges)
+				match := SearchMatch{
+					FilePath:   filePath,
+					FileName:   fileName,
+					LineNumber: lineNumber,
+					LineText:   displayText,
+					MatchStart: displayRanges[0].Start,
+					Ranges:     displayRanges,
+					Columns:    toUTF16Ranges(lineText, ranges),
+				}
+				if contextLines > 0 {
+					match.ContextBefore = append([]string{}, before...)
+					match.ContextAfter = []string{}
+					waitingForAfter = append(waitingForAfter, len(matches))
+				}
+				matches = append(matches, match)
+			}
+
+			if contextLines > 0 {
+				before = append(before, truncateDisplayLine(lineText))
+				if len(before) > contextLines {
+					before = before[1:]
+				}
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				return matches, lineNumber > 0
+			}
+			break
+		}
+	}
+
+	return matches, true
+}
+
+// isBinaryContent prüft anhand der ersten Bytes, ob Daten binär sind.
+// Wie bei git gilt ein NUL-Byte als sicheres Zeichen für Binärdaten.
+// Zusätzlich werden Daten mit vielen Steuerzeichen oder ungültigem UTF-8
+// als binär eingestuft (z.B. komprimierte Daten ohne NUL-Bytes).
+func isBinaryContent(head []byte) bool {
+	if len(head) == 0 {
+		return false
+	}
+
+	suspicious := 0
+	for i := 0; i < len(head); {
+		b := head[i]
+		if b == 0 {
+			return true
+		}
+		if b < utf8.RuneSelf {
+			// Steuerzeichen außer Tab, Zeilenumbruch, Formfeed, Escape
+			if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != 0x1b {
+				suspicious++
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRune(head[i:])
+		// Abgeschnittene Sequenz am Ende des Ausschnitts nicht werten
+		if r == utf8.RuneError && size == 1 && len(head)-i >= utf8.UTFMax {
+			suspicious++
+		}
+		i += size
+	}
+
+	return suspicious*10 > len(head)
+}
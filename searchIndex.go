@@ -0,0 +1,679 @@
+// searchIndex.go — Persistenter Trigramm-Index für die Projektsuche.
+// Für jedes über OpenProject geöffnete Projekt wird im Hintergrund ein Index
+// aufgebaut, der zu jeder Folge von drei Bytes (Trigramm) die Dateien kennt,
+// in denen sie vorkommt. Eine Suche muss dann nur noch die Dateien lesen,
+// die alle Trigramme des Suchbegriffs enthalten.
+//
+// Der Index liegt unter ~/.config/Leoedit/index/<hash>.gob und wird
+// regelmäßig inkrementell aktualisiert (nur geänderte Dateien werden neu
+// gelesen). Fehlt der Index oder wird er gerade erstmals aufgebaut, fällt
+// die Suche auf den normalen Verzeichnisdurchlauf zurück.
+//
+// Der Index liefert nur Kandidaten — jeder Treffer wird in der Datei selbst
+// geprüft, es gibt also keine falschen Treffer. Gespeicherte oder vom
+// Watcher gemeldete Änderungen werden bis zur nächsten Aktualisierung
+// vorgemerkt; Suchen, die sie betreffen, verwenden so lange den normalen
+// Verzeichnisdurchlauf und sind damit nie veraltet.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	searchIndexVersion         = 1
+	searchIndexRefreshInterval = 15 * time.Second
+	searchIndexMaxFileSize     = 4 * 1024 * 1024
+)
+
+// SearchIndexStatus beschreibt den Zustand des Index eines Projekts.
+type SearchIndexStatus struct {
+	RootPath     string `json:"rootPath"`
+	Exists       bool   `json:"exists"`       // Index ist geladen und wird für Suchen verwendet
+	Building     bool   `json:"building"`     // Aufbau oder Aktualisierung läuft
+	FileCount    int    `json:"fileCount"`    // Indizierte Dateien
+	TrigramCount int    `json:"trigramCount"` // Verschiedene Trigramme
+	BuiltAt      string `json:"builtAt"`      // Letzter vollständiger Aufbau (RFC3339)
+	UpdatedAt    string `json:"updatedAt"`    // Letzte Aktualisierung (RFC3339)
+	Stale        bool   `json:"stale"`        // Es gibt vorgemerkte, noch nicht übernommene Änderungen
+	ChangedFiles int    `json:"changedFiles"` // Anzahl vorgemerkter Pfade
+	Error        string `json:"error"`
+}
+
+// indexedFile ist eine Datei im Index. Wird eine Datei geändert oder
+// gelöscht, bleibt der alte Eintrag als Deleted stehen (die Posting-Listen
+// verweisen weiter auf seine ID) und die neue Version bekommt eine neue ID.
+// Binärdateien werden ohne Trigramme aufgenommen, damit sie bei der
+// Aktualisierung nicht jedes Mal neu gelesen werden.
+type indexedFile struct {
+	Path    string
+	ModTime int64
+	Size    int64
+	Binary  bool
+	Deleted bool
+}
+
+// trigramIndexData ist der persistierte Teil des Index.
+type trigramIndexData struct {
+	Version   int
+	Root      string
+	BuiltAt   time.Time
+	UpdatedAt time.Time
+	Files     []indexedFile
+	Postings  map[uint32][]uint32 // Trigramm -> aufsteigende Datei-IDs
+	deleted   int
+}
+
+// projectIndex verwaltet den Index eines Projekts und dessen Hintergrund-Job.
+type projectIndex struct {
+	root      string
+	storePath string
+	refresh   chan bool // true = vollständiger Neuaufbau
+	cancel    context.CancelFunc
+
+	mu       sync.RWMutex
+	data     *trigramIndexData
+	building bool
+	lastErr  string
+	dirty    map[string]uint64 // Vorgemerkte Pfade -> Änderungsnummer
+	dirtySeq uint64
+}
+
+// searchIndexes speichert die Indizes aller geöffneten Projekte (root -> index)
+var searchIndexes = make(map[string]*projectIndex)
+var searchIndexesMu sync.Mutex
+
+// GetSearchIndexStatus gibt den Zustand des Index für ein Projekt zurück.
+func (a *App) GetSearchIndexStatus(projectRoot string) SearchIndexStatus {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return SearchIndexStatus{Error: "Ungültiger Pfad: " + err.Error()}
+	}
+
+	searchIndexesMu.Lock()
+	idx := searchIndexes[root]
+	searchIndexesMu.Unlock()
+
+	status := SearchIndexStatus{RootPath: root}
+	if idx == nil {
+		return status
+	}
+
+	idx.mu.RLock()
+	data := idx.data
+	status.Building = idx.building
+	status.Error = idx.lastErr
+	if data != nil {
+		status.Exists = true
+		status.FileCount = len(data.Files) - data.deleted
+		status.TrigramCount = len(data.Postings)
+		status.BuiltAt = data.BuiltAt.Format(time.RFC3339)
+		status.UpdatedAt = data.UpdatedAt.Format(time.RFC3339)
+		status.ChangedFiles = len(idx.dirty)
+		status.Stale = status.ChangedFiles > 0
+	}
+	idx.mu.RUnlock()
+
+	return status
+}
+
+// RebuildSearchIndex baut den Index eines Projekts vollständig neu auf.
+// Läuft für das Projekt noch kein Index, wird er gestartet.
+func (a *App) RebuildSearchIndex(projectRoot string) error {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return fmt.Errorf("Ungültiger Pfad: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("Projektordner nicht gefunden: %s", root)
+	}
+
+	idx := startSearchIndex(root)
+	select {
+	case idx.refresh <- true:
+	default:
+		// Es wartet bereits eine Aktualisierung
+	}
+	return nil
+}
+
+// startSearchIndex startet den Hintergrund-Index für ein Projekt,
+// falls er noch nicht läuft.
+func startSearchIndex(root string) *projectIndex {
+	searchIndexesMu.Lock()
+	defer searchIndexesMu.Unlock()
+
+	if idx, exists := searchIndexes[root]; exists {
+		return idx
+	}
+
+	storePath := ""
+	if dir, err := getAppDataDir("index"); err == nil {
+		storePath = filepath.Join(dir, sha256String(root)[:16]+".gob")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	idx := &projectIndex{
+		root:      root,
+		storePath: storePath,
+		refresh:   make(chan bool, 1),
+		cancel:    cancel,
+	}
+	searchIndexes[root] = idx
+
+	go idx.run(ctx)
+	return idx
+}
+
+// stopAllSearchIndexes beendet alle Index-Jobs (beim Beenden der App).
+func stopAllSearchIndexes() {
+	searchIndexesMu.Lock()
+	defer searchIndexesMu.Unlock()
+
+	for root, idx := range searchIndexes {
+		idx.cancel()
+		delete(searchIndexes, root)
+	}
+}
+
+// findSearchIndex sucht den Index, dessen Projekt path enthält.
+// Bei verschachtelten Projekten gewinnt das innerste.
+func findSearchIndex(path string) *projectIndex {
+	searchIndexesMu.Lock()
+	defer searchIndexesMu.Unlock()
+
+	var best *projectIndex
+	for root, idx := range searchIndexes {
+		if isPathWithinRoot(path, root) && (best == nil || len(root) > len(best.root)) {
+			best = idx
+		}
+	}
+	return best
+}
+
+// markSearchIndexDirty merkt geänderte Pfade vor, bis der Index sie
+// übernommen hat, und stößt eine Aktualisierung an.
+func markSearchIndexDirty(paths ...string) {
+	for _, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		idx := findSearchIndex(path)
+		if idx == nil {
+			continue
+		}
+
+		idx.mu.Lock()
+		if idx.dirty == nil {
+			idx.dirty = make(map[string]uint64)
+		}
+		idx.dirtySeq++
+		idx.dirty[path] = idx.dirtySeq
+		idx.mu.Unlock()
+
+		select {
+		case idx.refresh <- false:
+		default:
+			// Es wartet bereits eine Aktualisierung
+		}
+	}
+}
+
+// indexedSearchCandidates liefert die Dateien unterhalb von rootPath, die
+// laut Index alle Pflicht-Trigramme des Matchers enthalten — sortiert in
+// Verzeichnisdurchlauf-Reihenfolge. ok ist false, wenn der Index die Suche
+// nicht vollständig abdeckt: kein Index geladen, größere Dateien erlaubt als
+// indiziert, rootPath in einem vom Index übersprungenen Ordner oder
+// vorgemerkte Änderungen unterhalb von rootPath.
+func indexedSearchCandidates(rootPath string, matcher *searchMatcher, maxFileSize int64) ([]string, bool) {
+	if maxFileSize > searchIndexMaxFileSize {
+		return nil, false
+	}
+	idx := findSearchIndex(rootPath)
+	if idx == nil || idx.excludesDir(rootPath) {
+		return nil, false
+	}
+
+	var required []uint32
+	for _, re := range matcher.patterns {
+		for _, lit := range requiredLiterals(re) {
+			required = append(required, trigramsOf([]byte(lit))...)
+		}
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	data := idx.data
+	if data == nil || idx.hasDirtyLocked(rootPath) {
+		return nil, false
+	}
+
+	var ids []uint32
+	if len(required) == 0 {
+		// Kein verwertbares Literal (z.B. ".*"): alle Dateien prüfen
+		ids = make([]uint32, len(data.Files))
+		for i := range data.Files {
+			ids[i] = uint32(i)
+		}
+	} else {
+		ids = data.Postings[required[0]]
+		for _, t := range required[1:] {
+			if len(ids) == 0 {
+				break
+			}
+			ids = intersectSorted(ids, data.Postings[t])
+		}
+	}
+
+	files := []string{}
+	for _, id := range ids {
+		f := data.Files[id]
+		if f.Deleted || f.Binary || f.Size > maxFileSize {
+			continue
+		}
+		if f.Path != rootPath && !isPathWithinRoot(f.Path, rootPath) {
+			continue
+		}
+		files = append(files, f.Path)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return lessWalkOrder(files[i], files[j])
+	})
+	return files, true
+}
+
+// excludesDir prüft, ob dir in einem Ordner liegt, den der Index-Aufbau
+// überspringt. walkSearchableFiles durchsucht einen solchen Ordner trotzdem,
+// wenn er selbst als Suchbereich gewählt wurde.
+func (idx *projectIndex) excludesDir(dir string) bool {
+	rel, err := filepath.Rel(idx.root, dir)
+	if err != nil || rel == "." {
+		return false
+	}
+
+	filter := newProjectPathFilter(idx.root, defaultSearchExcludes)
+	current := idx.root
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		if r, ok := filter.relPath(current); ok && filter.excludesEntry(r, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDirtyLocked prüft, ob vorgemerkte Änderungen rootPath betreffen
+// (Pfade darunter oder ein Ordner darüber). Aufrufer hält idx.mu.
+func (idx *projectIndex) hasDirtyLocked(rootPath string) bool {
+	for path := range idx.dirty {
+		if isPathWithinRoot(path, rootPath) || isPathWithinRoot(rootPath, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirtyMark liefert die aktuelle Änderungsnummer. Alles bis zu dieser
+// Nummer Vorgemerkte sieht ein danach beginnender Durchlauf.
+func (idx *projectIndex) dirtyMark() uint64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.dirtySeq
+}
+
+// clearDirtyLocked entfernt die bis mark vorgemerkten Pfade.
+// Aufrufer hält idx.mu.
+func (idx *projectIndex) clearDirtyLocked(mark uint64) {
+	for path, seq := range idx.dirty {
+		if seq <= mark {
+			delete(idx.dirty, path)
+		}
+	}
+}
+
+// run ist der Hintergrund-Job eines Projekt-Index.
+func (idx *projectIndex) run(ctx context.Context) {
+	if data := idx.load(); data != nil {
+		idx.mu.Lock()
+		idx.data = data
+		idx.mu.Unlock()
+		idx.update(ctx)
+	} else {
+		idx.build(ctx)
+	}
+
+	ticker := time.NewTicker(searchIndexRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case full := <-idx.refresh:
+			if full {
+				idx.build(ctx)
+			} else {
+				idx.update(ctx)
+			}
+		case <-ticker.C:
+			idx.update(ctx)
+		}
+	}
+}
+
+// setBuilding setzt den Status "Aufbau läuft" und den letzten Fehler.
+func (idx *projectIndex) setBuilding(building bool, errMsg string) {
+	idx.mu.Lock()
+	idx.building = building
+	idx.lastErr = errMsg
+	idx.mu.Unlock()
+}
+
+// build baut den Index vollständig neu auf und ersetzt den alten erst am Ende,
+// damit Suchen währenddessen den bisherigen Stand verwenden können.
+func (idx *projectIndex) build(ctx context.Context) {
+	idx.setBuilding(true, "")
+	mark := idx.dirtyMark()
+
+	now := time.Now()
+	data := &trigramIndexData{
+		Version:   searchIndexVersion,
+		Root:      idx.root,
+		BuiltAt:   now,
+		UpdatedAt: now,
+		Postings:  make(map[uint32][]uint32),
+	}
+
+	err := walkSearchableFiles(idx.root, searchIndexMaxFileSize, func(path string, info os.FileInfo) error {
+		if ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+		trigrams, ok := readFileTrigrams(path)
+		data.addFile(indexedFile{Path: path, ModTime: info.ModTime().UnixNano(), Size: info.Size(), Binary: !ok}, trigrams)
+		return nil
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		idx.setBuilding(false, "Index-Aufbau fehlgeschlagen: "+err.Error())
+		return
+	}
+
+	idx.mu.Lock()
+	idx.data = data
+	idx.clearDirtyLocked(mark)
+	idx.mu.Unlock()
+
+	idx.setBuilding(false, idx.save(data))
+}
+
+// update gleicht den Index mit dem Dateisystem ab und liest nur neue oder
+// geänderte Dateien. Sind zu viele Einträge veraltet, wird neu aufgebaut.
+// Schreibzugriffe auf idx.data passieren nur im Hintergrund-Job selbst,
+// daher braucht das Lesen hier keine Sperre.
+func (idx *projectIndex) update(ctx context.Context) {
+	data := idx.data
+	if data == nil {
+		idx.build(ctx)
+		return
+	}
+
+	idx.setBuilding(true, "")
+	mark := idx.dirtyMark()
+
+	type change struct {
+		file     indexedFile
+		trigrams []uint32
+	}
+	var changes []change
+
+	// Aktuelle Einträge nach Pfad
+	live := make(map[string]uint32, len(data.Files)-data.deleted)
+	for id, f := range data.Files {
+		if !f.Deleted {
+			live[f.Path] = uint32(id)
+		}
+	}
+	seen := make(map[string]bool, len(live))
+
+	walkSearchableFiles(idx.root, searchIndexMaxFileSize, func(path string, info os.FileInfo) error {
+		if ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+		seen[path] = true
+		f := indexedFile{Path: path, ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+		if id, ok := live[path]; ok && data.Files[id].ModTime == f.ModTime && data.Files[id].Size == f.Size {
+			return nil
+		}
+		trigrams, ok := readFileTrigrams(path)
+		f.Binary = !ok
+		changes = append(changes, change{file: f, trigrams: trigrams})
+		return nil
+	})
+	if ctx.Err() != nil {
+		return
+	}
+
+	var removed []uint32
+	for path, id := range live {
+		if !seen[path] {
+			removed = append(removed, id)
+		}
+	}
+	if len(changes) == 0 && len(removed) == 0 {
+		idx.mu.Lock()
+		idx.clearDirtyLocked(mark)
+		idx.mu.Unlock()
+		idx.setBuilding(false, "")
+		return
+	}
+
+	idx.mu.Lock()
+	idx.clearDirtyLocked(mark)
+	for _, id := range removed {
+		data.Files[id].Deleted = true
+		data.deleted++
+	}
+	for _, c := range changes {
+		if id, ok := live[c.file.Path]; ok {
+			data.Files[id].Deleted = true
+			data.deleted++
+		}
+		data.addFile(c.file, c.trigrams)
+	}
+	data.UpdatedAt = time.Now()
+	needsRebuild := data.deleted > 100 && data.deleted > len(data.Files)/4
+	idx.mu.Unlock()
+
+	if needsRebuild {
+		idx.build(ctx)
+		return
+	}
+
+	idx.setBuilding(false, idx.save(data))
+}
+
+// load liest einen gespeicherten Index. Gibt nil zurück, wenn keiner
+// existiert oder er nicht zum Projekt bzw. zur aktuellen Version passt.
+func (idx *projectIndex) load() *trigramIndexData {
+	if idx.storePath == "" {
+		return nil
+	}
+	file, err := os.Open(idx.storePath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var data trigramIndexData
+	if err := gob.NewDecoder(file).Decode(&data); err != nil {
+		return nil
+	}
+	if data.Version != searchIndexVersion || data.Root != idx.root || data.Postings == nil {
+		return nil
+	}
+	for _, f := range data.Files {
+		if f.Deleted {
+			data.deleted++
+		}
+	}
+	return &data
+}
+
+// save schreibt den Index auf die Festplatte.
+// Gibt eine Fehlermeldung zurück (leer bei Erfolg).
+func (idx *projectIndex) save(data *trigramIndexData) string {
+	if idx.storePath == "" {
+		return "Index-Verzeichnis nicht verfügbar"
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(data); err != nil {
+		return "Index konnte nicht gespeichert werden: " + err.Error()
+	}
+	if err := writeFileAtomic(idx.storePath, buf.Bytes()); err != nil {
+		return "Index konnte nicht gespeichert werden: " + err.Error()
+	}
+	return ""
+}
+
+// addFile nimmt eine Datei mit ihren Trigrammen in den Index auf.
+// Neue IDs sind immer die größten, die Posting-Listen bleiben sortiert.
+func (data *trigramIndexData) addFile(f indexedFile, trigrams []uint32) {
+	id := uint32(len(data.Files))
+	data.Files = append(data.Files, f)
+	for _, t := range trigrams {
+		data.Postings[t] = append(data.Postings[t], id)
+	}
+}
+
+// readFileTrigrams liest eine Datei und gibt ihre Trigramme zurück.
+// ok ist false für Binärdateien und nicht lesbare Dateien.
+func readFileTrigrams(path string) ([]uint32, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil || isBinaryContent(content[:min(len(content), binarySniffLen)]) {
+		return nil, false
+	}
+	return trigramsOf(content), true
+}
+
+// trigramsOf gibt die verschiedenen Trigramme eines Textes zurück.
+// Der Text wird vorher kleingeschrieben, damit der Index auch für Suchen
+// ohne Groß-/Kleinschreibung taugt. Trigramme über Zeilenumbrüche hinweg
+// werden ausgelassen, da die Suche zeilenweise arbeitet.
+func trigramsOf(text []byte) []uint32 {
+	text = bytes.ToLower(text)
+	seen := make(map[uint32]struct{})
+	for i := 0; i+3 <= len(text); i++ {
+		if text[i] == '\n' || text[i+1] == '\n' || text[i+2] == '\n' {
+			continue
+		}
+		seen[uint32(text[i])<<16|uint32(text[i+1])<<8|uint32(text[i+2])] = struct{}{}
+	}
+
+	trigrams := make([]uint32, 0, len(seen))
+	for t := range seen {
+		trigrams = append(trigrams, t)
+	}
+	return trigrams
+}
+
+// requiredLiterals ermittelt Zeichenketten, die in jedem Treffer eines
+// regulären Ausdrucks vorkommen müssen. Das ist eine einfache Näherung:
+// Alternativen und optionale Teile werden ignoriert, was nur die Anzahl der
+// Kandidaten erhöht, nie Treffer verliert.
+func requiredLiterals(re *regexp.Regexp) []string {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return nil
+	}
+
+	var literals []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			literals = append(literals, current.String())
+			current.Reset()
+		}
+	}
+
+	var walk func(node *syntax.Regexp)
+	walk = func(node *syntax.Regexp) {
+		switch node.Op {
+		case syntax.OpLiteral:
+			current.WriteString(string(node.Rune))
+		case syntax.OpConcat:
+			for _, sub := range node.Sub {
+				walk(sub)
+			}
+		case syntax.OpCapture:
+			walk(node.Sub[0])
+		case syntax.OpPlus:
+			// Mindestens ein Vorkommen, aber Wiederholungen trennen die Folge
+			flush()
+			walk(node.Sub[0])
+			flush()
+		case syntax.OpRepeat:
+			flush()
+			if node.Min >= 1 {
+				walk(node.Sub[0])
+				flush()
+			}
+		case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+			syntax.OpWordBoundary, syntax.OpNoWordBoundary, syntax.OpEmptyMatch:
+			// Nullbreite: unterbricht keine Literal-Folge
+		default:
+			flush()
+		}
+	}
+	walk(parsed)
+	flush()
+
+	return literals
+}
+
+// intersectSorted bildet die Schnittmenge zweier aufsteigend sortierter Listen.
+func intersectSorted(a, b []uint32) []uint32 {
+	result := make([]uint32, 0, min(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// lessWalkOrder vergleicht zwei Pfade in der Reihenfolge, in der
+// filepath.Walk sie besucht (Ordner für Ordner, jeweils nach Name sortiert).
+func lessWalkOrder(a, b string) bool {
+	partsA := strings.Split(a, string(filepath.Separator))
+	partsB := strings.Split(b, string(filepath.Separator))
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		if partsA[i] != partsB[i] {
+			return partsA[i] < partsB[i]
+		}
+	}
+	return len(partsA) < len(partsB)
+}
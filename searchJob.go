@@ -0,0 +1,212 @@
+// searchJob.go — Asynchrone, abbrechbare Suche für große Projekte.
+// Statt das Frontend bis zum Ende der Suche blockieren zu lassen, startet
+// StartSearch einen Hintergrund-Job und gibt sofort eine Such-ID zurück.
+// Treffer werden in Paketen über Wails-Events gestreamt:
+//   search_results_<id> → SearchProgress (neue Treffer + Fortschritt)
+//   search_done_<id>    → SearchSummary (Abschluss, Abbruch oder Fehler)
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Standardwerte für SearchLimits (greifen bei Werten <= 0)
+const (
+	defaultSearchJobMaxMatches = 10000
+	defaultSearchJobBatchSize  = 100
+	searchJobFlushInterval     = 100 * time.Millisecond
+)
+
+// SearchLimits begrenzt den Umfang eines Such-Jobs.
+type SearchLimits struct {
+	MaxMatches  int   `json:"maxMatches"`  // Abbruch nach so vielen Treffern
+	MaxFileSize int64 `json:"maxFileSize"` // Größere Dateien werden übersprungen (Bytes)
+	BatchSize   int   `json:"batchSize"`   // Treffer pro Event
+}
+
+// SearchJobRequest: Eingabedaten für StartSearch.
+type SearchJobRequest struct {
+	RootPath string        `json:"rootPath"`
+	Options  SearchOptions `json:"options"`
+	Scope    SearchScope   `json:"scope"`
+	Limits   SearchLimits  `json:"limits"`
+}
+
+// SearchProgress wird als search_results_<id> gesendet.
+// Matches enthält nur die seit dem letzten Event neu gefundenen Treffer.
+type SearchProgress struct {
+	SearchID      string        `json:"searchId"`
+	Matches       []SearchMatch `json:"matches"`
+	FilesSearched int           `json:"filesSearched"`
+	TotalMatches  int           `json:"totalMatches"`
+}
+
+// SearchSummary wird als search_done_<id> gesendet, wenn der Job endet.
+type SearchSummary struct {
+	SearchID      string `json:"searchId"`
+	FilesSearched int    `json:"filesSearched"`
+	TotalMatches  int    `json:"totalMatches"`
+	LimitReached  bool   `json:"limitReached"`
+	Cancelled     bool   `json:"cancelled"`
+	DurationMs    int64  `json:"durationMs"`
+	Error         string `json:"error"`
+}
+
+// searchJob repräsentiert eine laufende Suche.
+type searchJob struct {
+	ID     string
+	cancel context.CancelFunc
+}
+
+// searchJobs speichert alle laufenden Such-Jobs (searchId -> job)
+var searchJobs = make(map[string]*searchJob)
+var searchJobsMu sync.Mutex
+var searchJobCounter atomic.Uint64
+
+// StartSearch startet eine Suche im Hintergrund und gibt die Such-ID zurück.
+// Ungültige Eingaben (leerer Suchbegriff, fehlerhafter Regex, ungültiger
+// Suchbereich) werden sofort als Fehler gemeldet, ohne dass ein Job
+// gestartet wird.
+func (a *App) StartSearch(req SearchJobRequest) (string, error) {
+	if req.Options.Query == "" {
+		return "", fmt.Errorf("Suchbegriff darf nicht leer sein")
+	}
+
+	matcher, err := newSearchMatcher(req.Options)
+	if err != nil {
+		return "", err
+	}
+
+	rootPath, err := filepath.Abs(req.RootPath)
+	if err != nil {
+		return "", fmt.Errorf("Ungültiger Pfad: %w", err)
+	}
+
+	scope, err := resolveSearchScope(rootPath, req.Scope)
+	if err != nil {
+		return "", err
+	}
+
+	limits := req.Limits
+	if limits.MaxMatches <= 0 {
+		limits.MaxMatches = defaultSearchJobMaxMatches
+	}
+	if limits.MaxFileSize <= 0 {
+		limits.MaxFileSize = defaultMaxSearchFileSize
+	}
+	if limits.BatchSize <= 0 {
+		limits.BatchSize = defaultSearchJobBatchSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &searchJob{
+		ID:     fmt.Sprintf("search-%d", searchJobCounter.Add(1)),
+		cancel: cancel,
+	}
+
+	searchJobsMu.Lock()
+	searchJobs[job.ID] = job
+	searchJobsMu.Unlock()
+
+	go a.runSearchJob(ctx, job, scope, matcher, limits)
+
+	return job.ID, nil
+}
+
+// CancelSearch bricht einen laufenden Such-Job ab.
+// Der Job sendet danach noch search_done_<id> mit Cancelled = true.
+func (a *App) CancelSearch(searchId string) error {
+	searchJobsMu.Lock()
+	job, exists := searchJobs[searchId]
+	searchJobsMu.Unlock()
+
+	if !exists {
+		return nil // Bereits beendet
+	}
+
+	job.cancel()
+	return nil
+}
+
+// cancelAllSearches bricht alle laufenden Such-Jobs ab (beim Beenden der App).
+func cancelAllSearches() {
+	searchJobsMu.Lock()
+	defer searchJobsMu.Unlock()
+
+	for _, job := range searchJobs {
+		job.cancel()
+	}
+}
+
+// runSearchJob durchläuft das Verzeichnis und streamt Treffer ans Frontend.
+func (a *App) runSearchJob(ctx context.Context, job *searchJob, scope *searchScope, matcher *searchMatcher, limits SearchLimits) {
+	started := time.Now()
+	summary := SearchSummary{SearchID: job.ID}
+
+	batch := []SearchMatch{}
+	lastFlush := time.Now()
+
+	flush := func() {
+		if a.ctx != nil {
+			wailsRuntime.EventsEmit(a.ctx,
+				fmt.Sprintf("search_results_%s", job.ID),
+				SearchProgress{
+					SearchID:      job.ID,
+					Matches:       batch,
+					FilesSearched: summary.FilesSearched,
+					TotalMatches:  summary.TotalMatches,
+				})
+		}
+		batch = []SearchMatch{}
+		lastFlush = time.Now()
+	}
+
+	err := runParallelSearch(ctx, scope, matcher, limits.MaxFileSize, func(path string, matches []SearchMatch) bool {
+		summary.FilesSearched++
+
+		// Nicht mehr Treffer liefern als erlaubt
+		if remaining := limits.MaxMatches - summary.TotalMatches; len(matches) > remaining {
+			matches = matches[:remaining]
+		}
+		batch = append(batch, matches...)
+		summary.TotalMatches += len(matches)
+
+		if len(batch) >= limits.BatchSize || time.Since(lastFlush) >= searchJobFlushInterval {
+			flush()
+		}
+
+		if summary.TotalMatches >= limits.MaxMatches {
+			summary.LimitReached = true
+			return false
+		}
+		return true
+	})
+
+	if err != nil {
+		summary.Error = "Suchfehler: " + err.Error()
+	}
+	summary.Cancelled = ctx.Err() != nil
+
+	// Restliche Treffer senden
+	if len(batch) > 0 {
+		flush()
+	}
+
+	summary.DurationMs = time.Since(started).Milliseconds()
+	if a.ctx != nil {
+		wailsRuntime.EventsEmit(a.ctx, fmt.Sprintf("search_done_%s", job.ID), summary)
+	}
+
+	// Job bereinigen
+	searchJobsMu.Lock()
+	delete(searchJobs, job.ID)
+	searchJobsMu.Unlock()
+	job.cancel()
+}
@@ -0,0 +1,258 @@
+// searchScope.go — Eingrenzung der Suche.
+// Der Search Panel kann die Suche einschränken auf:
+//   - das gesamte Verzeichnis (Standard)
+//   - einen einzelnen Unterordner
+//   - die aktuell in Tabs geöffneten Dateien (inklusive ungespeicherter Änderungen)
+//
+// Zusätzlich gibt es "files to include" / "files to exclude" als kommagetrennte
+// Glob-Listen, z.B. "*.go,!*_test.go" oder "src/**/*.{ts,tsx}".
+// Die Globs verwenden die gitignore-Syntax (siehe ignore.go) und gelten
+// relativ zum RootPath der Suche.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Werte für SearchScope.Mode
+const (
+	SearchScopeAll       = "all"
+	SearchScopeFolder    = "folder"
+	SearchScopeOpenFiles = "openFiles"
+)
+
+// OpenBuffer ist eine im Editor geöffnete Datei mit ihrem aktuellen Inhalt.
+type OpenBuffer struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// SearchScope grenzt ein, welche Dateien durchsucht werden.
+//
+//	Mode:           "all" (Standard), "folder" oder "openFiles"
+//	Folder:         Unterordner für "folder" (absolut oder relativ zu RootPath)
+//	OpenFiles:      Geöffnete Tabs für "openFiles"; durchsucht wird Content,
+//	                nicht die Datei auf der Festplatte
+//	FilesToInclude: Nur Dateien, die auf eines der Muster passen
+//	FilesToExclude: Dateien, die auf eines der Muster passen, überspringen
+type SearchScope struct {
+	Mode           string       `json:"mode"`
+	Folder         string       `json:"folder"`
+	OpenFiles      []OpenBuffer `json:"openFiles"`
+	FilesToInclude string       `json:"filesToInclude"`
+	FilesToExclude string       `json:"filesToExclude"`
+}
+
+// SearchRequest: Eingabedaten für SearchInScope.
+type SearchRequest struct {
+	RootPath string        `json:"rootPath"`
+	Options  SearchOptions `json:"options"`
+	Scope    SearchScope   `json:"scope"`
+}
+
+// searchScope ist ein geprüfter SearchScope.
+type searchScope struct {
+	root     string       // RootPath der Suche, Bezug für die Globs
+	walkRoot string       // Durchlaufener Ordner (root oder Unterordner)
+	onlyOpen bool         // Nur geöffnete Dateien durchsuchen
+	buffers  []OpenBuffer // Geöffnete Dateien, die auf die Muster passen
+	includes []ignorePattern
+	excludes []ignorePattern
+}
+
+// resolveSearchScope prüft einen SearchScope und bereitet ihn auf.
+// rootPath muss bereits absolut sein.
+func resolveSearchScope(rootPath string, s SearchScope) (*searchScope, error) {
+	scope := &searchScope{root: rootPath, walkRoot: rootPath}
+
+	var err error
+	if scope.includes, err = parseGlobList(s.FilesToInclude); err != nil {
+		return nil, fmt.Errorf("Ungültiges Include-Muster: %w", err)
+	}
+	if scope.excludes, err = parseGlobList(s.FilesToExclude); err != nil {
+		return nil, fmt.Errorf("Ungültiges Exclude-Muster: %w", err)
+	}
+
+	switch s.Mode {
+	case "", SearchScopeAll:
+		if s.Folder != "" || len(s.OpenFiles) > 0 {
+			return nil, fmt.Errorf("Ordner und geöffnete Dateien werden nur mit dem passenden Modus verwendet")
+		}
+
+	case SearchScopeFolder:
+		if s.Folder == "" {
+			return nil, fmt.Errorf("Kein Ordner für die Suche angegeben")
+		}
+		folder := s.Folder
+		if !filepath.IsAbs(folder) {
+			folder = filepath.Join(rootPath, folder)
+		}
+		folder = filepath.Clean(folder)
+		if !isPathWithinRoot(folder, rootPath) {
+			return nil, fmt.Errorf("Ordner liegt außerhalb des Suchverzeichnisses: %s", s.Folder)
+		}
+		info, err := os.Stat(folder)
+		if err != nil {
+			return nil, fmt.Errorf("Ordner nicht gefunden: %s", s.Folder)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("Kein Ordner: %s", s.Folder)
+		}
+		scope.walkRoot = folder
+
+	case SearchScopeOpenFiles:
+		if len(s.OpenFiles) == 0 {
+			return nil, fmt.Errorf("Keine geöffneten Dateien zum Durchsuchen")
+		}
+		scope.onlyOpen = true
+		seen := make(map[string]bool)
+		for _, buf := range s.OpenFiles {
+			if buf.Path == "" {
+				return nil, fmt.Errorf("Geöffnete Datei ohne Pfad")
+			}
+			path, err := filepath.Abs(buf.Path)
+			if err != nil {
+				return nil, fmt.Errorf("Ungültiger Pfad: %w", err)
+			}
+			if seen[path] {
+				continue // Dieselbe Datei in mehreren Tabs
+			}
+			seen[path] = true
+			if scope.allows(path) {
+				scope.buffers = append(scope.buffers, OpenBuffer{Path: path, Content: buf.Content})
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("Unbekannter Suchbereich: %s", s.Mode)
+	}
+
+	return scope, nil
+}
+
+// allows prüft eine Datei gegen die include/exclude-Muster.
+// Muster werden gegen den Pfad relativ zu root geprüft; für Dateien
+// außerhalb von root (geöffnete Tabs) gegen den Dateinamen.
+// Wie bei .gitignore gewinnt das letzte passende Muster einer Liste.
+func (s *searchScope) allows(path string) bool {
+	if len(s.includes) == 0 && len(s.excludes) == 0 {
+		return true
+	}
+
+	rel := filepath.Base(path)
+	if r, err := filepath.Rel(s.root, path); err == nil && r != "." && r != ".." &&
+		!strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		rel = filepath.ToSlash(r)
+	}
+
+	if len(s.includes) > 0 && !matchGlobList(s.includes, rel) {
+		return false
+	}
+	return !matchGlobList(s.excludes, rel)
+}
+
+// matchGlobList prüft rel und alle übergeordneten Ordner gegen die Muster,
+// sodass z.B. "src/" oder "vendor" ganze Ordner erfassen.
+func matchGlobList(patterns []ignorePattern, rel string) bool {
+	matched := false
+	for _, p := range patterns {
+		sub := rel
+		for {
+			isFile := sub == rel
+			if !(p.dirOnly && isFile) && p.re.MatchString(sub) {
+				matched = !p.negate
+				break
+			}
+			idx := strings.LastIndexByte(sub, '/')
+			if idx < 0 {
+				break
+			}
+			sub = sub[:idx]
+		}
+	}
+	return matched
+}
+
+// parseGlobList zerlegt eine kommagetrennte Glob-Liste. Kommas innerhalb
+// geschweifter Klammern trennen keine Muster, sondern Alternativen
+// ("*.{ts,tsx}" entspricht "*.ts,*.tsx").
+func parseGlobList(list string) ([]ignorePattern, error) {
+	var patterns []ignorePattern
+	for _, item := range splitGlobList(list) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.Count(item, "{") != strings.Count(item, "}") {
+			return nil, fmt.Errorf("%q: Klammern nicht geschlossen", item)
+		}
+		for _, glob := range expandBraces(item) {
+			p, ok := parseIgnorePattern(glob)
+			if !ok {
+				return nil, fmt.Errorf("%q", item)
+			}
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns, nil
+}
+
+// splitGlobList trennt an Kommas außerhalb von geschweiften Klammern.
+func splitGlobList(list string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i := 0; i < len(list); i++ {
+		switch list[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				items = append(items, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(items, list[start:])
+}
+
+// expandBraces löst "{a,b}"-Alternativen auf (auch verschachtelt).
+// Ein vorangestelltes "!" bleibt bei allen Varianten erhalten.
+func expandBraces(glob string) []string {
+	open := strings.IndexByte(glob, '{')
+	if open < 0 {
+		return []string{glob}
+	}
+
+	// Passende schließende Klammer und Alternativen der obersten Ebene suchen
+	depth, start := 0, open+1
+	var alternatives []string
+	for i := open; i < len(glob); i++ {
+		switch glob[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, glob[start:i])
+				start = i + 1
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				alternatives = append(alternatives, glob[start:i])
+				var result []string
+				for _, alt := range alternatives {
+					result = append(result, expandBraces(glob[:open]+alt+glob[i+1:])...)
+				}
+				return result
+			}
+		}
+	}
+	return []string{glob}
+}
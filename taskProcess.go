@@ -0,0 +1,23 @@
+//go:build !windows
+
+// taskProcess.go - Prozessgruppen für Tasks (siehe taskRunner.go).
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// prepareTaskProcess startet den Task in einer eigenen Prozessgruppe,
+// damit beim Abbruch auch von der Shell gestartete Prozesse enden.
+func prepareTaskProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killTaskProcess beendet die ganze Prozessgruppe des Tasks.
+func killTaskProcess(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
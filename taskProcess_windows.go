@@ -0,0 +1,28 @@
+//go:build windows
+
+// taskProcess_windows.go - Beenden von Tasks unter Windows (siehe taskRunner.go).
+package main
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// prepareTaskProcess übergibt den Befehl unverändert an cmd.exe, da die
+// übliche Argument-Maskierung von Go Anführungszeichen für cmd verfälscht.
+func prepareTaskProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: strings.Join(cmd.Args, " ")}
+}
+
+// killTaskProcess beendet den Task samt Kindprozessen per taskkill /T.
+func killTaskProcess(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
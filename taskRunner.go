@@ -0,0 +1,459 @@
+// taskRunner.go — Build-/Run-Tasks eines Projekts.
+// Tasks werden in der .leoedit.json unter "tasks" definiert und über die
+// Shell im Projekt ausgeführt (sh -c bzw. cmd /C):
+//
+//	"tasks": [
+//	  {"name": "build", "command": "go build ./...", "problemMatcher": "go"},
+//	  {"name": "test", "command": "npm test", "cwd": "frontend", "env": {"CI": "1"}}
+//	]
+//
+// Ausgaben werden über Wails-Events gestreamt:
+//   task_output_<id> → TaskOutput (neue Ausgabe von stdout oder stderr)
+//   task_done_<id>   → TaskStatus (Ende, Abbruch oder Fehler)
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.GetProjectTasks(projectRoot) → []ProjectTask
+//   window.go.main.App.RunTask(projectRoot, name)   → Task-ID
+//   window.go.main.App.CancelTask(taskId)
+//   window.go.main.App.GetTaskStatus(taskId)        → TaskStatus
+//   window.go.main.App.GetTaskOutput(taskId)        → bisherige Ausgabe
+//   window.go.main.App.ListTaskRuns()               → []TaskStatus
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	taskMaxOutput   = 1 << 20 // Gespeicherte Ausgabe pro Lauf (für GetTaskOutput)
+	taskMaxFinished = 20      // Beendete Läufe, die für Statusabfragen erhalten bleiben
+	taskWaitDelay   = 5 * time.Second
+)
+
+// ProjectTask beschreibt einen Task in der .leoedit.json.
+// Cwd ist relativ zum Projektstamm (leer = Projektstamm) und muss im
+// Projekt liegen. Env ergänzt bzw. überschreibt die Umgebung der App.
+type ProjectTask struct {
+	Name           string            `json:"name"`
+	Command        string            `json:"command"`
+	Cwd            string            `json:"cwd,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	ProblemMatcher string            `json:"problemMatcher,omitempty"` // Name des Problem-Matchers, z.B. "go"
+
+	Extra map[string]json.RawMessage `json:"-"` // Unbekannte Schlüssel (siehe projectSchema.go)
+}
+
+// TaskOutput wird als task_output_<id> gesendet.
+type TaskOutput struct {
+	TaskID string `json:"taskId"`
+	Stream string `json:"stream"` // "stdout" oder "stderr"
+	Data   string `json:"data"`
+}
+
+// TaskStatus beschreibt einen laufenden oder beendeten Task-Lauf.
+// ExitCode ist -1, solange der Task läuft oder wenn er nicht regulär
+// beendet wurde.
+type TaskStatus struct {
+	TaskID      string `json:"taskId"`
+	Name        string `json:"name"`
+	ProjectRoot string `json:"projectRoot"`
+	Command     string `json:"command"`
+	Running     bool   `json:"running"`
+	ExitCode    int    `json:"exitCode"`
+	Cancelled   bool   `json:"cancelled"`
+	Started     string `json:"started"` // RFC3339
+	DurationMs  int64  `json:"durationMs"`
+	Problems    int    `json:"problems"` // Vom Problem-Matcher erkannte Probleme
+	Error       string `json:"error"`
+}
+
+// taskRun ist ein gestarteter Task.
+type taskRun struct {
+	mu       sync.Mutex
+	status   TaskStatus
+	started  time.Time
+	finished time.Time
+	output   []byte
+	problems *problemParser // nil ohne Problem-Matcher
+	cancel   context.CancelFunc
+}
+
+// taskRuns speichert laufende und zuletzt beendete Tasks (taskId -> run)
+var taskRuns = make(map[string]*taskRun)
+var taskRunsMu sync.Mutex
+var taskRunCounter atomic.Uint64
+
+// GetProjectTasks liefert die in der .leoedit.json definierten Tasks.
+func (a *App) GetProjectTasks(projectRoot string) ([]ProjectTask, error) {
+	config, err := readProjectConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if config.Tasks == nil {
+		return []ProjectTask{}, nil
+	}
+	return config.Tasks, nil
+}
+
+// RunTask startet den Task name des Projekts im Hintergrund und gibt die
+// Task-ID zurück. Läuft derselbe Task bereits, wird ein Fehler gemeldet.
+func (a *App) RunTask(projectRoot, name string) (string, error) {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return "", fmt.Errorf("Ungültiger Projektstamm: %w", err)
+	}
+
+	config, err := readProjectConfig(root)
+	if err != nil {
+		return "", err
+	}
+	var task *ProjectTask
+	for i := range config.Tasks {
+		if config.Tasks[i].Name == name {
+			task = &config.Tasks[i]
+			break
+		}
+	}
+	if task == nil {
+		return "", fmt.Errorf("Task nicht gefunden: %s", name)
+	}
+	if strings.TrimSpace(task.Command) == "" {
+		return "", fmt.Errorf("Task %s hat keinen Befehl", name)
+	}
+
+	dir := root
+	if task.Cwd != "" {
+		dir = task.Cwd
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+	}
+	dir, err = resolveProjectPath(dir, root, true)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("Arbeitsverzeichnis nicht gefunden: %s", dir)
+	}
+
+	var problems *problemParser
+	if task.ProblemMatcher != "" {
+		matcher, err := findProblemMatcher(task.ProblemMatcher, config.ProblemMatchers)
+		if err != nil {
+			return "", err
+		}
+		problems = newProblemParser(matcher, task.Name, dir, root)
+	}
+
+	taskRunsMu.Lock()
+	for _, run := range taskRuns {
+		run.mu.Lock()
+		busy := run.status.Running && run.status.ProjectRoot == root && run.status.Name == name
+		run.mu.Unlock()
+		if busy {
+			taskRunsMu.Unlock()
+			return "", fmt.Errorf("Task %s läuft bereits", name)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := taskCommand(ctx, task.Command)
+	cmd.Dir = dir
+	cmd.Env = taskEnv(task.Env)
+	cmd.WaitDelay = taskWaitDelay
+
+	now := time.Now()
+	run := &taskRun{
+		status: TaskStatus{
+			TaskID:      fmt.Sprintf("task-%d", taskRunCounter.Add(1)),
+			Name:        task.Name,
+			ProjectRoot: root,
+			Command:     task.Command,
+			Running:     true,
+			ExitCode:    -1,
+			Started:     now.Format(time.RFC3339),
+		},
+		started:  now,
+		problems: problems,
+		cancel:   cancel,
+	}
+
+	// Ausgabe über Writer statt Pipes lesen: cmd.Wait wartet dann höchstens
+	// taskWaitDelay auf Hintergrundprozesse, die stdout/stderr offen halten
+	cmd.Stdout = &taskOutputWriter{app: a, run: run, stream: "stdout"}
+	cmd.Stderr = &taskOutputWriter{app: a, run: run, stream: "stderr"}
+	if err := cmd.Start(); err != nil {
+		taskRunsMu.Unlock()
+		cancel()
+		return "", fmt.Errorf("Task konnte nicht gestartet werden: %w", err)
+	}
+
+	taskRuns[run.status.TaskID] = run
+	pruneTaskRuns()
+	taskRunsMu.Unlock()
+
+	// Probleme des letzten Laufs verwerfen
+	if problems != nil {
+		a.setProblems(root, task.Name, nil)
+	}
+
+	go a.runTask(ctx, run, cmd)
+
+	return run.status.TaskID, nil
+}
+
+// CancelTask bricht einen laufenden Task samt seiner Kindprozesse ab.
+// Der Task sendet danach noch task_done_<id> mit Cancelled = true.
+func (a *App) CancelTask(taskId string) error {
+	taskRunsMu.Lock()
+	run, exists := taskRuns[taskId]
+	taskRunsMu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("Task nicht gefunden: %s", taskId)
+	}
+
+	run.cancel()
+	return nil
+}
+
+// GetTaskStatus liefert den Status eines laufenden oder kürzlich beendeten Tasks.
+func (a *App) GetTaskStatus(taskId string) (TaskStatus, error) {
+	taskRunsMu.Lock()
+	run, exists := taskRuns[taskId]
+	taskRunsMu.Unlock()
+
+	if !exists {
+		return TaskStatus{}, fmt.Errorf("Task nicht gefunden: %s", taskId)
+	}
+	return run.snapshot(), nil
+}
+
+// GetTaskOutput liefert die bisherige Ausgabe eines Tasks (stdout und
+// stderr in der Reihenfolge des Eintreffens), z.B. nach dem erneuten
+// Öffnen des Ausgabe-Panels. Bei sehr viel Ausgabe nur das Ende.
+func (a *App) GetTaskOutput(taskId string) (string, error) {
+	taskRunsMu.Lock()
+	run, exists := taskRuns[taskId]
+	taskRunsMu.Unlock()
+
+	if !exists {
+		return "", fmt.Errorf("Task nicht gefunden: %s", taskId)
+	}
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	return string(run.output), nil
+}
+
+// ListTaskRuns liefert alle laufenden und kürzlich beendeten Tasks,
+// die neuesten zuerst.
+func (a *App) ListTaskRuns() []TaskStatus {
+	taskRunsMu.Lock()
+	runs := make([]*taskRun, 0, len(taskRuns))
+	for _, run := range taskRuns {
+		runs = append(runs, run)
+	}
+	taskRunsMu.Unlock()
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].started.After(runs[j].started)
+	})
+	result := make([]TaskStatus, 0, len(runs))
+	for _, run := range runs {
+		result = append(result, run.snapshot())
+	}
+	return result
+}
+
+// cancelAllTasks bricht alle laufenden Tasks ab (beim Beenden der App).
+func cancelAllTasks() {
+	taskRunsMu.Lock()
+	defer taskRunsMu.Unlock()
+
+	for _, run := range taskRuns {
+		run.cancel()
+	}
+}
+
+// runTask wartet auf das Ende des Prozesses und meldet es dem Frontend.
+// Die Ausgabe läuft währenddessen über taskOutputWriter.
+func (a *App) runTask(ctx context.Context, run *taskRun, cmd *exec.Cmd) {
+	err := cmd.Wait()
+	if errors.Is(err, exec.ErrWaitDelay) {
+		// Prozess ist beendet, nur ein Kindprozess hielt die Ausgabe offen
+		err = nil
+	}
+
+	if run.problems != nil {
+		run.mu.Lock()
+		if run.problems.finish() {
+			a.setProblems(run.status.ProjectRoot, run.status.Name, run.problems.problems)
+		}
+		run.mu.Unlock()
+	}
+
+	run.mu.Lock()
+	run.finished = time.Now()
+	run.status.Running = false
+	run.status.Cancelled = ctx.Err() != nil
+	if cmd.ProcessState != nil && !run.status.Cancelled {
+		run.status.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if err != nil && !run.status.Cancelled {
+		if _, isExit := err.(*exec.ExitError); !isExit {
+			run.status.Error = "Task-Fehler: " + err.Error()
+		}
+	}
+	run.status.DurationMs = run.finished.Sub(run.started).Milliseconds()
+	status := run.status
+	run.mu.Unlock()
+
+	if a.ctx != nil {
+		wailsRuntime.EventsEmit(a.ctx, fmt.Sprintf("task_done_%s", status.TaskID), status)
+	}
+	run.cancel()
+}
+
+// taskOutputWriter nimmt einen Ausgabekanal des Prozesses entgegen, sendet
+// jedes Stück ans Frontend und hängt es an die gespeicherte Ausgabe an.
+type taskOutputWriter struct {
+	app    *App
+	run    *taskRun
+	stream string
+}
+
+func (w *taskOutputWriter) Write(p []byte) (int, error) {
+	data := string(p)
+	w.run.appendOutput(p)
+	w.app.matchTaskProblems(w.run, w.stream, data)
+	if w.app.ctx != nil {
+		wailsRuntime.EventsEmit(w.app.ctx,
+			fmt.Sprintf("task_output_%s", w.run.status.TaskID),
+			TaskOutput{TaskID: w.run.status.TaskID, Stream: w.stream, Data: data})
+	}
+	return len(p), nil
+}
+
+// appendOutput speichert Ausgabe, höchstens die letzten taskMaxOutput Bytes.
+func (r *taskRun) appendOutput(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.output = append(r.output, data...)
+	if over := len(r.output) - taskMaxOutput; over > 0 {
+		r.output = append(r.output[:0], r.output[over:]...)
+	}
+}
+
+// matchTaskProblems wertet Ausgabe mit dem Problem-Matcher des Tasks aus
+// und sendet neu erkannte Probleme sofort (wichtig für Watch-Tasks).
+func (a *App) matchTaskProblems(run *taskRun, stream, data string) {
+	if run.problems == nil {
+		return
+	}
+
+	// Unter run.mu senden, damit stdout und stderr sich nicht mit
+	// veralteten Listen überholen
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if run.problems.feed(stream, data) {
+		a.setProblems(run.status.ProjectRoot, run.status.Name, run.problems.problems)
+	}
+}
+
+// snapshot liefert den aktuellen Status (mit laufender Dauer).
+func (r *taskRun) snapshot() TaskStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := r.status
+	if r.problems != nil {
+		status.Problems = len(r.problems.problems)
+	}
+	if status.Running {
+		status.DurationMs = time.Since(r.started).Milliseconds()
+	}
+	return status
+}
+
+// pruneTaskRuns entfernt die ältesten beendeten Läufe, sobald mehr als
+// taskMaxFinished vorhanden sind. Muss mit gesperrtem taskRunsMu
+// aufgerufen werden.
+func pruneTaskRuns() {
+	var finished []*taskRun
+	for _, run := range taskRuns {
+		run.mu.Lock()
+		if !run.status.Running {
+			finished = append(finished, run)
+		}
+		run.mu.Unlock()
+	}
+	if len(finished) <= taskMaxFinished {
+		return
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].finished.Before(finished[j].finished)
+	})
+	for _, run := range finished[:len(finished)-taskMaxFinished] {
+		delete(taskRuns, run.status.TaskID)
+	}
+}
+
+// taskCommand erstellt den Shell-Aufruf für command. Beim Abbruch über
+// ctx wird die ganze Prozessgruppe beendet (siehe killTaskProcess).
+func taskCommand(ctx context.Context, command string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	}
+	prepareTaskProcess(cmd)
+	cmd.Cancel = func() error {
+		return killTaskProcess(cmd)
+	}
+	return cmd
+}
+
+// taskEnv ergänzt die Umgebung der App um die Variablen des Tasks.
+func taskEnv(extra map[string]string) []string {
+	env := os.Environ()
+	if len(extra) == 0 {
+		return env
+	}
+
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Überschriebene Variablen entfernen, damit der Task-Wert gilt
+	result := make([]string, 0, len(env)+len(extra))
+	for _, entry := range env {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, overridden := extra[key]; !overridden {
+			result = append(result, entry)
+		}
+	}
+	for _, key := range keys {
+		result = append(result, key+"="+extra[key])
+	}
+	return result
+}
@@ -0,0 +1,306 @@
+// textEncoding.go — Erkennung und Erhalt von Zeichenkodierung und Zeilenenden.
+// Textdateien werden beim Lesen nach UTF-8 dekodiert und beim Speichern
+// wieder in ihre ursprüngliche Kodierung gebracht, damit z.B. alte
+// Latin-1-Dateien mit Umlauten nicht zerstört werden.
+//
+// Unterstützt werden:
+//   utf-8         (mit oder ohne BOM)
+//   utf-16le/be   (mit BOM, ohne BOM per Heuristik)
+//   windows-1252  (Standard für Dateien, die kein gültiges UTF-8 sind)
+//   iso-8859-1    (nur beim Speichern wählbar, wird als windows-1252 erkannt)
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Werte für TextFormat.Encoding
+const (
+	EncodingUTF8        = "utf-8"
+	EncodingUTF16LE     = "utf-16le"
+	EncodingUTF16BE     = "utf-16be"
+	EncodingWindows1252 = "windows-1252"
+	EncodingISO88591    = "iso-8859-1"
+)
+
+// Werte für TextFormat.LineEnding
+const (
+	LineEndingLF   = "lf"
+	LineEndingCRLF = "crlf"
+	LineEndingCR   = "cr"
+)
+
+// TextFormat beschreibt Kodierung und Zeilenenden einer Textdatei.
+// MixedLineEndings wird nur beim Lesen gesetzt; LineEnding ist dann das
+// häufigste Zeilenende.
+type TextFormat struct {
+	Encoding         string `json:"encoding"`
+	BOM              bool   `json:"bom"`
+	LineEnding       string `json:"lineEnding"`
+	MixedLineEndings bool   `json:"mixedLineEndings"`
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// Zeichen 0x80–0x9F in Windows-1252. Nicht belegte Positionen (0)
+// werden wie bei ISO-8859-1 auf das gleichnamige Steuerzeichen abgebildet.
+var windows1252High = [32]rune{
+	0x20AC, 0, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017D, 0,
+	0, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0, 0x017E, 0x0178,
+}
+
+// decodeTextFile erkennt das Format von data und dekodiert den Inhalt.
+func decodeTextFile(data []byte) (string, TextFormat) {
+	format := detectTextFormat(data)
+	content := decodeText(data, format)
+	format.LineEnding, format.MixedLineEndings = detectLineEnding(content)
+	return content, format
+}
+
+// detectTextFormat erkennt Kodierung und BOM (ohne Zeilenenden).
+func detectTextFormat(data []byte) TextFormat {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return TextFormat{Encoding: EncodingUTF8, BOM: true}
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return TextFormat{Encoding: EncodingUTF16LE, BOM: true}
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return TextFormat{Encoding: EncodingUTF16BE, BOM: true}
+	}
+
+	if enc := guessUTF16(data); enc != "" {
+		return TextFormat{Encoding: enc}
+	}
+	if utf8.Valid(data) {
+		return TextFormat{Encoding: EncodingUTF8}
+	}
+	return TextFormat{Encoding: EncodingWindows1252}
+}
+
+// guessUTF16 erkennt UTF-16 ohne BOM an den Null-Bytes, die bei
+// überwiegend lateinischem Text jedes zweite Byte belegen.
+func guessUTF16(data []byte) string {
+	sample := data[:min(len(data), binarySniffLen)]
+	if len(sample) < 4 || len(sample)%2 != 0 {
+		return ""
+	}
+
+	evenZeros, oddZeros := 0, 0
+	for i := 0; i < len(sample); i += 2 {
+		if sample[i] == 0 {
+			evenZeros++
+		}
+		if sample[i+1] == 0 {
+			oddZeros++
+		}
+	}
+
+	pairs := len(sample) / 2
+	switch {
+	case oddZeros*10 >= pairs*4 && evenZeros*10 < pairs:
+		return EncodingUTF16LE
+	case evenZeros*10 >= pairs*4 && oddZeros*10 < pairs:
+		return EncodingUTF16BE
+	}
+	return ""
+}
+
+// decodeText wandelt data in einen UTF-8-String um. Eine BOM wird entfernt.
+func decodeText(data []byte, format TextFormat) string {
+	switch format.Encoding {
+	case EncodingUTF16LE, EncodingUTF16BE:
+		if format.BOM {
+			data = data[2:]
+		}
+		var order binary.ByteOrder = binary.LittleEndian
+		if format.Encoding == EncodingUTF16BE {
+			order = binary.BigEndian
+		}
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			units[i] = order.Uint16(data[2*i:])
+		}
+		return string(utf16.Decode(units))
+
+	case EncodingWindows1252, EncodingISO88591:
+		var sb strings.Builder
+		sb.Grow(len(data))
+		for _, b := range data {
+			r := rune(b)
+			if format.Encoding == EncodingWindows1252 && b >= 0x80 && b < 0xA0 && windows1252High[b-0x80] != 0 {
+				r = windows1252High[b-0x80]
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String()
+
+	default:
+		if format.BOM {
+			data = bytes.TrimPrefix(data, bomUTF8)
+		}
+		return string(data)
+	}
+}
+
+// encodeText wandelt content in die Kodierung von format um.
+// Zeichen, die in der Zielkodierung nicht darstellbar sind, ergeben einen
+// Fehler mit Zeilennummer, statt stillschweigend ersetzt zu werden.
+func encodeText(content string, format TextFormat) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch format.Encoding {
+	case "", EncodingUTF8:
+		if format.BOM {
+			buf.Write(bomUTF8)
+		}
+		buf.WriteString(content)
+
+	case EncodingUTF16LE, EncodingUTF16BE:
+		var order binary.ByteOrder = binary.LittleEndian
+		bom := bomUTF16LE
+		if format.Encoding == EncodingUTF16BE {
+			order, bom = binary.BigEndian, bomUTF16BE
+		}
+		if format.BOM {
+			buf.Write(bom)
+		}
+		unit := make([]byte, 2)
+		for _, u := range utf16.Encode([]rune(content)) {
+			order.PutUint16(unit, u)
+			buf.Write(unit)
+		}
+
+	case EncodingWindows1252, EncodingISO88591:
+		buf.Grow(len(content))
+		line := 1
+		for _, r := range content {
+			b, ok := encodeSingleByte(r, format.Encoding)
+			if !ok {
+				return nil, fmt.Errorf("Zeichen %q in Zeile %d kann nicht als %s gespeichert werden", r, line, format.Encoding)
+			}
+			if r == '\n' {
+				line++
+			}
+			buf.WriteByte(b)
+		}
+
+	default:
+		return nil, fmt.Errorf("Unbekannte Zeichenkodierung: %s", format.Encoding)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeSingleByte bildet ein Zeichen auf ein Byte in Windows-1252 bzw.
+// ISO-8859-1 ab.
+func encodeSingleByte(r rune, encoding string) (byte, bool) {
+	if encoding == EncodingWindows1252 {
+		for i, c := range windows1252High {
+			if c == r {
+				return byte(0x80 + i), true
+			}
+		}
+		// Belegte Positionen 0x80–0x9F stehen nicht für ihr Steuerzeichen
+		if r >= 0x80 && r < 0xA0 && windows1252High[r-0x80] != 0 {
+			return 0, false
+		}
+	}
+	if r < 0x100 {
+		return byte(r), true
+	}
+	return 0, false
+}
+
+// detectLineEnding zählt die Zeilenenden und liefert das häufigste.
+// Dateien ohne Zeilenumbruch gelten als LF.
+func detectLineEnding(content string) (string, bool) {
+	crlf, lf, cr := 0, 0, 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\r':
+			if i+1 < len(content) && content[i+1] == '\n' {
+				crlf++
+				i++
+			} else {
+				cr++
+			}
+		case '\n':
+			lf++
+		}
+	}
+
+	kinds := 0
+	for _, n := range []int{crlf, lf, cr} {
+		if n > 0 {
+			kinds++
+		}
+	}
+
+	ending := LineEndingLF
+	switch {
+	case crlf > lf && crlf >= cr:
+		ending = LineEndingCRLF
+	case cr > lf && cr > crlf:
+		ending = LineEndingCR
+	}
+	return ending, kinds > 1
+}
+
+// applyLineEnding ersetzt alle Zeilenenden in content durch ending.
+func applyLineEnding(content, ending string) string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	normalized = strings.ReplaceAll(normalized, "\r", "\n")
+	switch ending {
+	case LineEndingCRLF:
+		return strings.ReplaceAll(normalized, "\n", "\r\n")
+	case LineEndingCR:
+		return strings.ReplaceAll(normalized, "\n", "\r")
+	}
+	return normalized
+}
+
+// encodeForSave bereitet content zum Speichern in filename vor.
+// Leere Felder in requested übernehmen das Format der bestehenden Datei:
+//   - Ohne Encoding werden Kodierung und BOM der Datei beibehalten,
+//     sonst gelten Encoding und BOM aus requested.
+//   - Ohne LineEnding werden die Zeilenenden der Datei wiederhergestellt,
+//     sofern der Editor sie zu LF vereinheitlicht hat und die Datei nicht
+//     gemischte Zeilenenden hatte.
+func encodeForSave(filename, content string, requested TextFormat) ([]byte, error) {
+	format := TextFormat{Encoding: EncodingUTF8, LineEnding: LineEndingLF}
+	if data, err := os.ReadFile(filename); err == nil {
+		_, format = decodeTextFile(data)
+	}
+
+	if requested.Encoding != "" {
+		format.Encoding = requested.Encoding
+		format.BOM = requested.BOM
+	}
+
+	switch requested.LineEnding {
+	case "", LineEndingLF, LineEndingCRLF, LineEndingCR:
+	default:
+		return nil, fmt.Errorf("Unbekanntes Zeilenende: %s", requested.LineEnding)
+	}
+
+	switch {
+	case requested.LineEnding != "":
+		content = applyLineEnding(content, requested.LineEnding)
+	case format.LineEnding != LineEndingLF && !format.MixedLineEndings && !strings.Contains(content, "\r"):
+		content = applyLineEnding(content, format.LineEnding)
+	}
+
+	return encodeText(content, format)
+}
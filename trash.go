@@ -0,0 +1,367 @@
+// trash.go — Papierkorb statt endgültigem Löschen.
+// DeleteFile verschiebt Dateien in den Papierkorb nach der freedesktop.org-
+// Spezifikation (~/.local/share/Trash bzw. .Trash-<uid> auf anderen
+// Laufwerken, siehe trash_linux.go). Jeder Eintrag besteht aus
+//   <Papierkorb>/files/<Name>            → die gelöschte Datei bzw. der Ordner
+//   <Papierkorb>/info/<Name>.trashinfo   → ursprünglicher Pfad und Löschzeit
+//
+// Ist das nicht möglich (andere Systeme, kein Schreibrecht), wird ein
+// eigener Papierkorb mit demselben Aufbau unter
+// <Konfigurationsordner>/Leoedit/trash verwendet.
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.DeleteFile(path)             → in den Papierkorb
+//   window.go.main.App.DeleteFilePermanently(path)  → endgültig löschen
+//   window.go.main.App.ListTrash()                  → []TrashItem, neueste zuerst
+//   window.go.main.App.RestoreFromTrash(trashId)    → wiederhergestellter Pfad
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	trashInfoExt        = ".trashinfo"
+	trashInfoDateFormat = "2006-01-02T15:04:05" // Ortszeit, laut Spezifikation ohne Zeitzone
+)
+
+// TrashItem beschreibt einen Eintrag im Papierkorb.
+// ID ist für RestoreFromTrash bestimmt und sonst nicht auszuwerten.
+type TrashItem struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	OriginalPath string `json:"originalPath"`
+	DeletedAt    string `json:"deletedAt"` // RFC3339
+	IsDirectory  bool   `json:"isDirectory"`
+	Size         int64  `json:"size"`
+}
+
+// trashDir ist ein Papierkorb-Ordner. Bei Papierkörben auf anderen
+// Laufwerken sind die Pfade in .trashinfo relativ zu topDir.
+type trashDir struct {
+	path   string
+	topDir string // Leer: Pfade sind absolut
+}
+
+// DeleteFile verschiebt eine Datei oder einen Ordner in den Papierkorb.
+// Endgültiges Löschen: DeleteFilePermanently.
+func (a *App) DeleteFile(path string) error {
+	absPath, err := a.checkDeletable(path)
+	if err != nil {
+		return err
+	}
+	trashID, err := moveToTrash(absPath)
+	if err != nil {
+		return fmt.Errorf("Löschen fehlgeschlagen: %w", err)
+	}
+	recordFileOperation(FileOpDelete, "Löschen: "+filepath.Base(absPath),
+		[]string{absPath}, trashStep(absPath, trashID))
+	return nil
+}
+
+// DeleteFilePermanently löscht eine Datei oder einen Ordner (inkl. Inhalt)
+// endgültig, ohne Papierkorb.
+func (a *App) DeleteFilePermanently(path string) error {
+	absPath, err := a.checkDeletable(path)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(absPath); err != nil {
+		return fmt.Errorf("Löschen fehlgeschlagen: %w", err)
+	}
+	return nil
+}
+
+// ListTrash listet alle Einträge der bekannten Papierkörbe, neueste zuerst.
+func (a *App) ListTrash() []TrashItem {
+	items := []TrashItem{}
+	for _, dir := range knownTrashDirs() {
+		entries, err := os.ReadDir(filepath.Join(dir.path, "info"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), trashInfoExt) {
+				continue
+			}
+			if item, err := readTrashItem(dir, filepath.Join(dir.path, "info", entry.Name())); err == nil {
+				items = append(items, item)
+			}
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].DeletedAt > items[j].DeletedAt })
+	return items
+}
+
+// RestoreFromTrash stellt einen Eintrag an seinem ursprünglichen Ort wieder
+// her. Existiert dort inzwischen etwas anderes, wird "foo (2).txt" verwendet.
+// Gibt den Pfad zurück, unter dem der Eintrag wiederhergestellt wurde.
+func (a *App) RestoreFromTrash(trashId string) (string, error) {
+	return restoreFromTrash(trashId, "")
+}
+
+// restoreFromTrash stellt einen Eintrag nach target wieder her. Ist target
+// leer, wird der ursprüngliche Pfad verwendet und bei Bedarf umbenannt;
+// ein ausdrücklich angegebenes target darf nicht existieren.
+func restoreFromTrash(trashId, target string) (string, error) {
+	dir, ok := trashDirForInfo(trashId)
+	if !ok || !strings.HasSuffix(trashId, trashInfoExt) {
+		return "", fmt.Errorf("Ungültiger Papierkorb-Eintrag")
+	}
+	item, err := readTrashItem(dir, trashId)
+	if err != nil {
+		return "", err
+	}
+
+	stored := filepath.Join(dir.path, "files", strings.TrimSuffix(filepath.Base(trashId), trashInfoExt))
+	if target == "" {
+		target = item.OriginalPath
+		if _, err := os.Lstat(target); err == nil {
+			target = uniqueFileName(target)
+		}
+	} else if _, err := os.Lstat(target); err == nil {
+		return "", fmt.Errorf("Ziel existiert bereits: %s", filepath.Base(target))
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", fmt.Errorf("Ordner konnte nicht angelegt werden: %w", err)
+	}
+
+	if err := moveAcrossDevices(stored, target); err != nil {
+		return "", fmt.Errorf("Wiederherstellen fehlgeschlagen: %w", err)
+	}
+	os.Remove(trashId)
+	return target, nil
+}
+
+// checkDeletable normalisiert path und verhindert das Löschen von Root- und
+// Home-Verzeichnis.
+func (a *App) checkDeletable(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("Pfad darf nicht leer sein")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("Ungültiger Pfad: %w", err)
+	}
+
+	// Sicherheitscheck: Root- und Home-Verzeichnis nicht löschen
+	home := a.GetHomeDirectory()
+	if absPath == "/" || absPath == home || filepath.Dir(absPath) == absPath {
+		return "", fmt.Errorf("Dieses Verzeichnis darf nicht gelöscht werden")
+	}
+
+	// Im Projekt: bei Symlinks nur den Link löschen, nie den Projektstamm
+	if absPath, err = guardProjectPath(absPath, false); err != nil {
+		return "", err
+	}
+	if root := currentProjectRoot(); root != "" && samePath(absPath, root, caseInsensitiveFS) {
+		return "", fmt.Errorf("Dieses Verzeichnis darf nicht gelöscht werden")
+	}
+
+	if _, err := os.Lstat(absPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("Datei existiert nicht: %s", absPath)
+	}
+	return absPath, nil
+}
+
+// moveToTrash verschiebt path in den ersten passenden Papierkorb und gibt
+// die ID des neuen Eintrags zurück. Liegt path auf einem anderen Laufwerk
+// als der Papierkorb, wird der nächste versucht; der eigene Papierkorb der
+// App ist der letzte Ausweg und kopiert notfalls.
+func moveToTrash(path string) (string, error) {
+	var lastErr error
+	for _, dir := range systemTrashDirs(path) {
+		id, err := trashInto(dir, path, false)
+		if err == nil {
+			return id, nil
+		}
+		lastErr = err
+	}
+
+	dir, err := appTrashDir()
+	if err != nil {
+		if lastErr != nil {
+			return "", lastErr
+		}
+		return "", err
+	}
+	return trashInto(dir, path, true)
+}
+
+// trashInto legt den .trashinfo-Eintrag an und verschiebt path in dir.
+// Der Name wird über das exklusive Anlegen der .trashinfo-Datei reserviert.
+func trashInto(dir trashDir, path string, allowCopy bool) (string, error) {
+	filesDir := filepath.Join(dir.path, "files")
+	infoDir := filepath.Join(dir.path, "info")
+	if err := os.MkdirAll(filesDir, 0700); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(infoDir, 0700); err != nil {
+		return "", err
+	}
+
+	stored := path
+	if dir.topDir != "" {
+		rel, err := filepath.Rel(dir.topDir, path)
+		if err != nil {
+			return "", err
+		}
+		stored = rel
+	}
+	info := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: filepath.ToSlash(stored)}).EscapedPath(),
+		time.Now().Format(trashInfoDateFormat))
+
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	if stem == "" {
+		stem, ext = base, ""
+	}
+
+	for n := 1; ; n++ {
+		name := base
+		if n > 1 {
+			name = stem + "." + strconv.Itoa(n) + ext
+		}
+		infoPath := filepath.Join(infoDir, name+trashInfoExt)
+
+		file, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = file.WriteString(info)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			target := filepath.Join(filesDir, name)
+			if allowCopy {
+				err = moveAcrossDevices(path, target)
+			} else {
+				err = os.Rename(path, target)
+			}
+		}
+		if err != nil {
+			os.Remove(infoPath)
+			return "", err
+		}
+		return infoPath, nil
+	}
+}
+
+// moveAcrossDevices benennt src in dst um und kopiert, falls beide auf
+// verschiedenen Laufwerken liegen.
+func moveAcrossDevices(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := (*fileCopier)(nil).copyTree(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// readTrashItem liest eine .trashinfo-Datei.
+func readTrashItem(dir trashDir, infoPath string) (TrashItem, error) {
+	file, err := os.Open(infoPath)
+	if err != nil {
+		return TrashItem{}, fmt.Errorf("Papierkorb-Eintrag nicht gefunden: %w", err)
+	}
+	defer file.Close()
+
+	var rawPath, rawDate string
+	inSection := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inSection = line == "[Trash Info]"
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || !inSection {
+			continue
+		}
+		switch key {
+		case "Path":
+			rawPath = value
+		case "DeletionDate":
+			rawDate = value
+		}
+	}
+
+	original, err := url.PathUnescape(rawPath)
+	if err != nil || original == "" {
+		return TrashItem{}, fmt.Errorf("Papierkorb-Eintrag ist beschädigt: %s", infoPath)
+	}
+	original = filepath.FromSlash(original)
+	if !filepath.IsAbs(original) {
+		original = filepath.Join(dir.topDir, original)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(infoPath), trashInfoExt)
+	item := TrashItem{
+		ID:           infoPath,
+		Name:         filepath.Base(original),
+		OriginalPath: filepath.Clean(original),
+	}
+	if deleted, err := time.ParseInLocation(trashInfoDateFormat, rawDate, time.Local); err == nil {
+		item.DeletedAt = deleted.Format(time.RFC3339)
+	}
+	if info, err := os.Lstat(filepath.Join(dir.path, "files", name)); err == nil {
+		item.IsDirectory = info.IsDir()
+		item.Size = info.Size()
+	} else {
+		return TrashItem{}, fmt.Errorf("Papierkorb-Eintrag ohne Datei: %s", name)
+	}
+	return item, nil
+}
+
+// knownTrashDirs liefert alle Papierkörbe, die ListTrash durchsucht.
+func knownTrashDirs() []trashDir {
+	dirs := allSystemTrashDirs()
+	if dir, err := appTrashDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// trashDirForInfo prüft, dass infoPath im info-Ordner eines bekannten
+// Papierkorbs liegt, und gibt diesen zurück.
+func trashDirForInfo(infoPath string) (trashDir, bool) {
+	infoPath = filepath.Clean(infoPath)
+	for _, dir := range knownTrashDirs() {
+		if filepath.Dir(infoPath) == filepath.Join(dir.path, "info") {
+			return dir, true
+		}
+	}
+	return trashDir{}, false
+}
+
+// appTrashDir ist der eigene Papierkorb der App (Rückfall).
+func appTrashDir() (trashDir, error) {
+	path, err := getAppDataDir("trash")
+	if err != nil {
+		return trashDir{}, err
+	}
+	os.Chmod(path, 0700)
+	return trashDir{path: path}, nil
+}
@@ -0,0 +1,126 @@
+//go:build linux
+
+// trash_linux.go — Papierkörbe nach freedesktop.org für trash.go.
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// systemTrashDirs liefert die Papierkörbe, in die path verschoben werden
+// kann, in der Reihenfolge der Spezifikation: zuerst der Papierkorb im
+// Home-Verzeichnis, dann der des Laufwerks, auf dem path liegt.
+func systemTrashDirs(path string) []trashDir {
+	var dirs []trashDir
+	if home, ok := homeTrashDir(); ok {
+		dirs = append(dirs, home)
+	}
+
+	topDir := mountPoint(filepath.Dir(path))
+	if topDir == "" {
+		return dirs
+	}
+	uid := strconv.Itoa(os.Getuid())
+
+	// $topdir/.Trash nur verwenden, wenn es ein echter Ordner mit Sticky-Bit ist
+	if info, err := os.Lstat(filepath.Join(topDir, ".Trash")); err == nil &&
+		info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		dirs = append(dirs, trashDir{path: filepath.Join(topDir, ".Trash", uid), topDir: topDir})
+	}
+	return append(dirs, trashDir{path: filepath.Join(topDir, ".Trash-"+uid), topDir: topDir})
+}
+
+// allSystemTrashDirs liefert alle vorhandenen Papierkörbe (Home-Verzeichnis
+// und eingehängte Laufwerke).
+func allSystemTrashDirs() []trashDir {
+	var dirs []trashDir
+	if home, ok := homeTrashDir(); ok {
+		dirs = append(dirs, home)
+	}
+
+	file, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return dirs
+	}
+	defer file.Close()
+
+	uid := strconv.Itoa(os.Getuid())
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		topDir := unescapeMountPath(fields[1])
+		if seen[topDir] {
+			continue
+		}
+		seen[topDir] = true
+
+		for _, candidate := range []string{filepath.Join(topDir, ".Trash", uid), filepath.Join(topDir, ".Trash-"+uid)} {
+			if info, err := os.Lstat(candidate); err == nil && info.IsDir() {
+				dirs = append(dirs, trashDir{path: candidate, topDir: topDir})
+			}
+		}
+	}
+	return dirs
+}
+
+// homeTrashDir ist $XDG_DATA_HOME/Trash (Standard: ~/.local/share/Trash).
+func homeTrashDir() (trashDir, bool) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return trashDir{}, false
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return trashDir{path: filepath.Join(dataHome, "Trash")}, true
+}
+
+// mountPoint sucht den obersten Ordner oberhalb von dir, der noch auf
+// demselben Laufwerk liegt.
+func mountPoint(dir string) string {
+	var st syscall.Stat_t
+	if err := syscall.Stat(dir, &st); err != nil {
+		return ""
+	}
+
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		var pst syscall.Stat_t
+		if err := syscall.Stat(parent, &pst); err != nil || pst.Dev != st.Dev {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// unescapeMountPath dekodiert Oktal-Escapes in /proc/self/mounts ("\040" = Leerzeichen).
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, `\`) {
+		return path
+	}
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if n, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				sb.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		sb.WriteByte(path[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+// trash_other.go - Ohne freedesktop.org-Papierkorb wird nur der eigene
+// Papierkorb der App verwendet (siehe trash.go).
+package main
+
+func systemTrashDirs(path string) []trashDir {
+	return nil
+}
+
+func allSystemTrashDirs() []trashDir {
+	return nil
+}
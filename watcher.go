@@ -0,0 +1,553 @@
+// watcher.go — Dateiüberwachung für geöffnete Dateien und den Projektordner.
+// Änderungen auf der Festplatte (z.B. durch git oder andere Programme) werden
+// gesammelt, zusammengefasst und gebündelt als Wails-Event gesendet:
+//   file_changes → []FileChangeEvent
+//
+// Frontend-Aufrufe:
+//   window.go.main.App.WatchFile(path)      → Datei in einem Tab überwachen
+//   window.go.main.App.UnwatchFile(path)    → Tab geschlossen
+//   window.go.main.App.WatchProject(root)   → Projektordner rekursiv überwachen
+//   window.go.main.App.UnwatchProject()
+//
+// Überwacht werden immer Ordner, nicht die Dateien selbst: Viele Programme
+// speichern atomar (temporäre Datei schreiben, dann umbenennen), wodurch eine
+// Überwachung der alten Datei ins Leere laufen würde.
+// Die eigentliche Betriebssystem-Schnittstelle liegt in watcher_linux.go.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Werte für FileChangeEvent.Type
+const (
+	FileChangeCreated  = "created"
+	FileChangeModified = "modified"
+	FileChangeDeleted  = "deleted"
+	FileChangeRenamed  = "renamed"
+)
+
+// Events werden gesendet, wenn watchDebounce lang nichts passiert ist,
+// spätestens aber nach watchMaxDelay.
+const (
+	watchDebounce = 100 * time.Millisecond
+	watchMaxDelay = 500 * time.Millisecond
+)
+
+// FileChangeEvent beschreibt eine Änderung auf der Festplatte.
+// OldPath ist nur bei "renamed" gesetzt.
+type FileChangeEvent struct {
+	Type        string `json:"type"`
+	Path        string `json:"path"`
+	OldPath     string `json:"oldPath,omitempty"`
+	IsDirectory bool   `json:"isDirectory"`
+}
+
+// Arten von Rohereignissen der Betriebssystem-Schnittstelle
+const (
+	watchOpCreate = iota
+	watchOpWrite
+	watchOpRemove
+	watchOpMovedFrom
+	watchOpMovedTo
+	watchOpOverflow // Ereignisse gingen verloren
+)
+
+// rawWatchEvent ist ein einzelnes, noch nicht zusammengefasstes Ereignis.
+// cookie verbindet movedFrom und movedTo einer Umbenennung.
+type rawWatchEvent struct {
+	op     int
+	path   string
+	isDir  bool
+	cookie uint32
+}
+
+// watchBackend ist die Betriebssystem-Schnittstelle (inotify unter Linux).
+// Ereignisse werden an den beim Erstellen übergebenen Handler geliefert.
+type watchBackend interface {
+	add(dir string) error
+	remove(dir string)
+	close()
+}
+
+// watchedDir beschreibt einen überwachten Ordner.
+// inProject: alle Einträge melden; sonst nur die geöffneten Dateien in files.
+type watchedDir struct {
+	inProject bool
+	files     map[string]int // Dateiname -> Anzahl Tabs
+}
+
+// fileWatcher sammelt Rohereignisse und sendet sie gebündelt.
+type fileWatcher struct {
+	mu      sync.Mutex
+	backend watchBackend
+	emit    func(events []FileChangeEvent)
+
+	dirs        map[string]*watchedDir
+	projectRoot string
+	filter      *pathFilter
+
+	pending    []FileChangeEvent
+	moves      map[uint32]rawWatchEvent // Offene Umbenennungen (cookie -> movedFrom)
+	timer      *time.Timer
+	batchStart time.Time
+}
+
+// fsWatcher ist der gemeinsame Watcher der App (wird bei Bedarf erstellt).
+var fsWatcher *fileWatcher
+var fsWatcherMu sync.Mutex
+
+// WatchFile überwacht eine in einem Tab geöffnete Datei.
+// Mehrfache Aufrufe für dieselbe Datei werden gezählt.
+func (a *App) WatchFile(path string) error {
+	if path == "" {
+		return fmt.Errorf("Pfad darf nicht leer sein")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("Ungültiger Pfad: %w", err)
+	}
+
+	w, err := a.getFileWatcher()
+	if err != nil {
+		return err
+	}
+	return w.watchFile(path)
+}
+
+// UnwatchFile beendet die Überwachung einer Datei (Tab geschlossen).
+func (a *App) UnwatchFile(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("Ungültiger Pfad: %w", err)
+	}
+
+	fsWatcherMu.Lock()
+	w := fsWatcher
+	fsWatcherMu.Unlock()
+	if w != nil {
+		w.unwatchFile(path)
+	}
+	return nil
+}
+
+// WatchProject überwacht einen Projektordner samt Unterordnern.
+// Eine vorherige Projektüberwachung wird ersetzt. Per .gitignore oder
+// Projektkonfiguration ausgeschlossene Ordner werden nicht überwacht.
+func (a *App) WatchProject(projectRoot string) error {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return fmt.Errorf("Ungültiger Pfad: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("Projektordner nicht gefunden: %s", root)
+	}
+
+	w, err := a.getFileWatcher()
+	if err != nil {
+		return err
+	}
+	return w.watchProject(root)
+}
+
+// UnwatchProject beendet die Überwachung des Projektordners.
+// Geöffnete Dateien werden weiter überwacht.
+func (a *App) UnwatchProject() error {
+	fsWatcherMu.Lock()
+	w := fsWatcher
+	fsWatcherMu.Unlock()
+	if w != nil {
+		w.unwatchProject()
+	}
+	return nil
+}
+
+// getFileWatcher liefert den gemeinsamen Watcher und startet ihn beim
+// ersten Aufruf.
+func (a *App) getFileWatcher() (*fileWatcher, error) {
+	fsWatcherMu.Lock()
+	defer fsWatcherMu.Unlock()
+
+	if fsWatcher != nil {
+		return fsWatcher, nil
+	}
+
+	w := &fileWatcher{
+		dirs:  make(map[string]*watchedDir),
+		moves: make(map[uint32]rawWatchEvent),
+		emit: func(events []FileChangeEvent) {
+			if a.ctx != nil {
+				wailsRuntime.EventsEmit(a.ctx, "file_changes", events)
+			}
+		},
+	}
+	backend, err := newWatchBackend(w.handle)
+	if err != nil {
+		return nil, err
+	}
+	w.backend = backend
+	fsWatcher = w
+	return w, nil
+}
+
+// stopFileWatcher beendet die Dateiüberwachung (beim Beenden der App).
+func stopFileWatcher() {
+	fsWatcherMu.Lock()
+	defer fsWatcherMu.Unlock()
+
+	if fsWatcher != nil {
+		fsWatcher.backend.close()
+		fsWatcher.mu.Lock()
+		if fsWatcher.timer != nil {
+			fsWatcher.timer.Stop()
+		}
+		fsWatcher.mu.Unlock()
+		fsWatcher = nil
+	}
+}
+
+// watchFile überwacht den Ordner einer Datei und merkt sich den Dateinamen.
+func (w *fileWatcher) watchFile(path string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	dir, name := filepath.Dir(path), filepath.Base(path)
+	d, ok := w.dirs[dir]
+	if !ok {
+		if err := w.backend.add(dir); err != nil {
+			return fmt.Errorf("Ordner kann nicht überwacht werden: %w", err)
+		}
+		d = &watchedDir{files: make(map[string]int)}
+		w.dirs[dir] = d
+	}
+	d.files[name]++
+	return nil
+}
+
+// unwatchFile verringert den Zähler einer Datei und gibt den Ordner frei,
+// wenn er nicht mehr benötigt wird.
+func (w *fileWatcher) unwatchFile(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	dir, name := filepath.Dir(path), filepath.Base(path)
+	d, ok := w.dirs[dir]
+	if !ok || d.files[name] == 0 {
+		return
+	}
+	d.files[name]--
+	if d.files[name] == 0 {
+		delete(d.files, name)
+	}
+	w.releaseDir(dir)
+}
+
+// watchProject ersetzt die Projektüberwachung durch root.
+func (w *fileWatcher) watchProject(root string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.projectRoot == root {
+		return nil
+	}
+	w.clearProject()
+
+	w.projectRoot = root
+	w.filter = newProjectPathFilter(root, defaultSearchExcludes)
+	return w.addProjectTree(root)
+}
+
+// unwatchProject beendet die Projektüberwachung.
+func (w *fileWatcher) unwatchProject() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.clearProject()
+}
+
+// clearProject gibt alle Projektordner frei. Aufrufer hält w.mu.
+func (w *fileWatcher) clearProject() {
+	for dir, d := range w.dirs {
+		if d.inProject {
+			d.inProject = false
+			w.releaseDir(dir)
+		}
+	}
+	w.projectRoot = ""
+	w.filter = nil
+}
+
+// addProjectTree überwacht dir und alle nicht ausgeschlossenen Unterordner.
+// Aufrufer hält w.mu.
+func (w *fileWatcher) addProjectTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil // Nicht lesbare Unterordner ignorieren
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != w.projectRoot && w.isFiltered(path, true) {
+			return filepath.SkipDir
+		}
+
+		d, ok := w.dirs[path]
+		if !ok {
+			if err := w.backend.add(path); err != nil {
+				if path == dir {
+					return fmt.Errorf("Ordner kann nicht überwacht werden: %w", err)
+				}
+				return nil
+			}
+			d = &watchedDir{files: make(map[string]int)}
+			w.dirs[path] = d
+		}
+		d.inProject = true
+		return nil
+	})
+}
+
+// removeProjectTree beendet die Überwachung von dir und allen Unterordnern
+// (Ordner wurde gelöscht oder verschoben). Aufrufer hält w.mu.
+func (w *fileWatcher) removeProjectTree(dir string) {
+	for path, d := range w.dirs {
+		if d.inProject && isPathWithinRoot(path, dir) {
+			d.inProject = false
+			w.releaseDir(path)
+		}
+	}
+}
+
+// releaseDir beendet die Überwachung eines Ordners, der weder zum Projekt
+// gehört noch geöffnete Dateien enthält. Aufrufer hält w.mu.
+func (w *fileWatcher) releaseDir(dir string) {
+	d, ok := w.dirs[dir]
+	if !ok || d.inProject || len(d.files) > 0 {
+		return
+	}
+	w.backend.remove(dir)
+	delete(w.dirs, dir)
+}
+
+// isFiltered prüft, ob ein Pfad im Projekt ausgeblendet ist.
+func (w *fileWatcher) isFiltered(path string, isDir bool) bool {
+	if w.filter == nil {
+		return false
+	}
+	rel, ok := w.filter.relPath(path)
+	return ok && w.filter.excludesEntry(rel, isDir)
+}
+
+// isRelevant prüft, ob ein Ereignis gemeldet werden soll. Aufrufer hält w.mu.
+func (w *fileWatcher) isRelevant(path string, isDir bool) bool {
+	d, ok := w.dirs[filepath.Dir(path)]
+	if !ok {
+		return false
+	}
+	if d.files[filepath.Base(path)] > 0 {
+		return true
+	}
+	return d.inProject && !w.isFiltered(path, isDir)
+}
+
+// handle verarbeitet ein Rohereignis der Betriebssystem-Schnittstelle.
+func (w *fileWatcher) handle(ev rawWatchEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	switch ev.op {
+	case watchOpOverflow:
+		// Ereignisse verloren: den Projektordner als geändert melden,
+		// damit das Frontend neu lädt
+		if w.projectRoot != "" {
+			w.add(FileChangeEvent{Type: FileChangeModified, Path: w.projectRoot, IsDirectory: true})
+		}
+
+	case watchOpCreate:
+		if ev.isDir && w.isProjectDir(filepath.Dir(ev.path)) && !w.isFiltered(ev.path, true) {
+			w.addProjectTree(ev.path)
+		}
+		if w.isRelevant(ev.path, ev.isDir) {
+			w.add(FileChangeEvent{Type: FileChangeCreated, Path: ev.path, IsDirectory: ev.isDir})
+		}
+
+	case watchOpWrite:
+		if w.isRelevant(ev.path, ev.isDir) {
+			w.add(FileChangeEvent{Type: FileChangeModified, Path: ev.path, IsDirectory: ev.isDir})
+		}
+
+	case watchOpRemove:
+		if ev.isDir {
+			w.removeProjectTree(ev.path)
+		}
+		if w.isRelevant(ev.path, ev.isDir) {
+			w.add(FileChangeEvent{Type: FileChangeDeleted, Path: ev.path, IsDirectory: ev.isDir})
+		}
+
+	case watchOpMovedFrom:
+		if ev.isDir {
+			w.removeProjectTree(ev.path)
+		}
+		// Wird beim Senden zu "deleted", falls kein passendes movedTo folgt
+		w.moves[ev.cookie] = ev
+		w.schedule()
+
+	case watchOpMovedTo:
+		if ev.isDir && w.isProjectDir(filepath.Dir(ev.path)) && !w.isFiltered(ev.path, true) {
+			w.addProjectTree(ev.path)
+		}
+		from, paired := w.moves[ev.cookie]
+		delete(w.moves, ev.cookie)
+
+		fromRelevant := paired && w.isRelevant(from.path, from.isDir)
+		toRelevant := w.isRelevant(ev.path, ev.isDir)
+		switch {
+		case fromRelevant && toRelevant:
+			w.add(FileChangeEvent{Type: FileChangeRenamed, Path: ev.path, OldPath: from.path, IsDirectory: ev.isDir})
+		case fromRelevant:
+			w.add(FileChangeEvent{Type: FileChangeDeleted, Path: from.path, IsDirectory: from.isDir})
+		case toRelevant:
+			// Von außerhalb hereinverschoben oder atomar gespeichert
+			w.add(FileChangeEvent{Type: FileChangeModified, Path: ev.path, IsDirectory: ev.isDir})
+		}
+	}
+}
+
+// isProjectDir prüft, ob dir zum überwachten Projekt gehört. Aufrufer hält w.mu.
+func (w *fileWatcher) isProjectDir(dir string) bool {
+	d, ok := w.dirs[dir]
+	return ok && d.inProject
+}
+
+// add fügt ein Ereignis zum aktuellen Paket hinzu und fasst es mit einem
+// vorherigen Ereignis für denselben Pfad zusammen. Aufrufer hält w.mu.
+//
+//	created  + modified → created
+//	created  + deleted  → (entfällt)
+//	deleted  + created  → modified (Datei ersetzt)
+//	created  + renamed  → modified am Ziel (atomares Speichern über eine
+//	                      temporäre Datei)
+func (w *fileWatcher) add(ev FileChangeEvent) {
+	if ev.Type == FileChangeRenamed {
+		if i := w.pendingIndex(ev.OldPath); i >= 0 {
+			prev := w.pending[i]
+			w.pending = append(w.pending[:i], w.pending[i+1:]...)
+			if prev.Type == FileChangeCreated {
+				ev = FileChangeEvent{Type: FileChangeModified, Path: ev.Path, IsDirectory: ev.IsDirectory}
+			}
+		}
+	}
+
+	if i := w.pendingIndex(ev.Path); i >= 0 {
+		prev := w.pending[i]
+		switch {
+		case prev.Type == FileChangeCreated && ev.Type == FileChangeModified:
+			ev = prev
+		case prev.Type == FileChangeCreated && ev.Type == FileChangeDeleted:
+			w.pending = append(w.pending[:i], w.pending[i+1:]...)
+			w.schedule()
+			return
+		case prev.Type == FileChangeDeleted && ev.Type == FileChangeCreated:
+			ev.Type = FileChangeModified
+		case prev.Type == FileChangeRenamed && ev.Type == FileChangeModified:
+			ev = prev
+		}
+		w.pending[i] = ev
+	} else {
+		w.pending = append(w.pending, ev)
+	}
+	w.schedule()
+}
+
+// pendingIndex sucht das wartende Ereignis für path. Aufrufer hält w.mu.
+func (w *fileWatcher) pendingIndex(path string) int {
+	for i, ev := range w.pending {
+		if ev.Path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// schedule startet oder verlängert die Wartezeit bis zum Senden.
+// Aufrufer hält w.mu.
+func (w *fileWatcher) schedule() {
+	if w.timer == nil {
+		w.batchStart = time.Now()
+		w.timer = time.AfterFunc(watchDebounce, w.flush)
+		return
+	}
+	if time.Since(w.batchStart) < watchMaxDelay-watchDebounce {
+		w.timer.Reset(watchDebounce)
+	}
+}
+
+// flush sendet alle gesammelten Ereignisse.
+func (w *fileWatcher) flush() {
+	w.mu.Lock()
+
+	// Umbenennungen ohne Ziel: Datei wurde aus dem überwachten Bereich verschoben
+	for cookie, from := range w.moves {
+		delete(w.moves, cookie)
+		if w.isRelevant(from.path, from.isDir) {
+			w.add(FileChangeEvent{Type: FileChangeDeleted, Path: from.path, IsDirectory: from.isDir})
+		}
+	}
+
+	events := w.pending
+	w.pending = nil
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	projectRoot := w.projectRoot
+	w.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	// Caches des Projekts verwerfen, Änderungen für den Index vormerken
+	if projectRoot != "" {
+		invalidated := false
+		for _, ev := range events {
+			if ev.OldPath != "" && isPathWithinRoot(ev.OldPath, projectRoot) {
+				markSearchIndexDirty(ev.OldPath)
+			}
+			if !isPathWithinRoot(ev.Path, projectRoot) {
+				continue
+			}
+			markSearchIndexDirty(ev.Path)
+			if !invalidated {
+				invalidateProjectCaches(projectRoot)
+				invalidated = true
+			}
+		}
+	}
+
+	w.emit(events)
+}
+
+// invalidateProjectCaches verwirft die Dateiliste der Dateisuche und stößt
+// eine Aktualisierung des Such-Index an.
+func invalidateProjectCaches(root string) {
+	fileListCacheMu.Lock()
+	delete(fileListCache, root)
+	fileListCacheMu.Unlock()
+
+	if idx := findSearchIndex(root); idx != nil {
+		select {
+		case idx.refresh <- false:
+		default:
+			// Es wartet bereits eine Aktualisierung
+		}
+	}
+}
@@ -0,0 +1,153 @@
+//go:build linux
+
+// watcher_linux.go — inotify-Anbindung für die Dateiüberwachung (watcher.go).
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Ereignisse, die für jeden überwachten Ordner abonniert werden
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY |
+	syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO |
+	syscall.IN_ONLYDIR
+
+// inotifyBackend überwacht Ordner per inotify.
+type inotifyBackend struct {
+	fd      int
+	file    *os.File // Nur zum Lesen; Fd() würde den Deskriptor blockierend machen
+	handler func(rawWatchEvent)
+
+	mu    sync.Mutex
+	paths map[int32]string // Watch-Deskriptor -> Ordner
+	wds   map[string]int32 // Ordner -> Watch-Deskriptor
+}
+
+// newWatchBackend öffnet eine inotify-Instanz und startet die Lese-Goroutine.
+func newWatchBackend(handler func(rawWatchEvent)) (watchBackend, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("Dateiüberwachung konnte nicht gestartet werden: %w", err)
+	}
+
+	b := &inotifyBackend{
+		// Nicht blockierender Deskriptor: Close beendet ein laufendes Read
+		fd:      fd,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		handler: handler,
+		paths:   make(map[int32]string),
+		wds:     make(map[string]int32),
+	}
+	go b.readEvents()
+	return b, nil
+}
+
+// add überwacht einen Ordner.
+func (b *inotifyBackend) add(dir string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.wds[dir]; exists {
+		return nil
+	}
+	wd, err := syscall.InotifyAddWatch(b.fd, dir, inotifyMask)
+	if err != nil {
+		if err == syscall.ENOSPC {
+			return fmt.Errorf("Limit für überwachte Ordner erreicht (fs.inotify.max_user_watches)")
+		}
+		return err
+	}
+	b.paths[int32(wd)] = dir
+	b.wds[dir] = int32(wd)
+	return nil
+}
+
+// remove beendet die Überwachung eines Ordners.
+func (b *inotifyBackend) remove(dir string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wd, exists := b.wds[dir]
+	if !exists {
+		return
+	}
+	syscall.InotifyRmWatch(b.fd, uint32(wd))
+	delete(b.wds, dir)
+	delete(b.paths, wd)
+}
+
+// close beendet die Überwachung und die Lese-Goroutine.
+func (b *inotifyBackend) close() {
+	b.file.Close()
+}
+
+// readEvents liest Ereignisse, bis die inotify-Instanz geschlossen wird.
+func (b *inotifyBackend) readEvents() {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := b.file.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			raw := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			nameEnd := nameStart + int(raw.Len)
+			if nameEnd > n {
+				break
+			}
+			name := strings.TrimRight(string(buf[nameStart:nameEnd]), "\x00")
+			offset = nameEnd
+
+			b.dispatch(raw.Wd, raw.Mask, raw.Cookie, name)
+		}
+	}
+}
+
+// dispatch übersetzt ein inotify-Ereignis in ein rawWatchEvent.
+func (b *inotifyBackend) dispatch(wd int32, mask, cookie uint32, name string) {
+	if mask&syscall.IN_Q_OVERFLOW != 0 {
+		b.handler(rawWatchEvent{op: watchOpOverflow})
+		return
+	}
+
+	b.mu.Lock()
+	dir, ok := b.paths[wd]
+	if ok && mask&syscall.IN_IGNORED != 0 {
+		// Ordner gelöscht oder Überwachung entfernt
+		delete(b.paths, wd)
+		delete(b.wds, dir)
+	}
+	b.mu.Unlock()
+	if !ok || name == "" {
+		return
+	}
+
+	ev := rawWatchEvent{
+		path:   filepath.Join(dir, name),
+		isDir:  mask&syscall.IN_ISDIR != 0,
+		cookie: cookie,
+	}
+	switch {
+	case mask&syscall.IN_CREATE != 0:
+		ev.op = watchOpCreate
+	case mask&(syscall.IN_MODIFY|syscall.IN_CLOSE_WRITE) != 0:
+		ev.op = watchOpWrite
+	case mask&syscall.IN_DELETE != 0:
+		ev.op = watchOpRemove
+	case mask&syscall.IN_MOVED_FROM != 0:
+		ev.op = watchOpMovedFrom
+	case mask&syscall.IN_MOVED_TO != 0:
+		ev.op = watchOpMovedTo
+	default:
+		return
+	}
+	b.handler(ev)
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+// watcher_other.go - Stub für Systeme ohne inotify.
+package main
+
+import "fmt"
+
+func newWatchBackend(handler func(rawWatchEvent)) (watchBackend, error) {
+	return nil, fmt.Errorf("Dateiüberwachung wird auf diesem System nicht unterstützt")
+}